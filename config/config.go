@@ -9,6 +9,28 @@ import (
 
 const DefaultConfig = "config/default.json"
 
+// eviction policies, volatile policies evict only objects w expiration time
+const (
+	NoEviction     = "noeviction"
+	AllKeysLRU     = "allkeys-lru"
+	AllKeysLFU     = "allkeys-lfu"
+	VolatileLRU    = "volatile-lru"
+	VolatileTTL    = "volatile-ttl"
+	VolatileRandom = "volatile-random"
+)
+
+// default limits of stored procedures
+const (
+	DefaultProcedureMaxSteps = 1000
+	DefaultProcedureTimeout  = 10 * time.Millisecond
+)
+
+// expiration strategies
+const (
+	ExpirationIndex    = "index"
+	ExpirationSampling = "sampling"
+)
+
 type (
 	BaseAuthConfig struct {
 		User string `json:"user"`
@@ -21,12 +43,74 @@ type (
 		RefreshTime         time.Duration `json:"refresh_time_in_seconds"`
 		RefreshTimeout      time.Duration `json:"refresh_timeout_in_seconds"`
 		MaxRefreshes        int           `json:"max_concurrent_refreshes"`
+
+		// lock striping of collections, collection w less than 2 shards uses single lock
+		ShardsCount int `json:"shards_count"`
+
+		// memory limit in bytes of keys and values for all collections, 0 - unlimited
+		MaxMemory      int64  `json:"maxmemory"`
+		EvictionPolicy string `json:"eviction_policy"`
+
+		// settings of collections by name, override storage settings
+		Collections map[string]CollectionConfig `json:"collections"`
+
+		Expiration ExpirationConfig `json:"expiration"`
+
+		Procedures ProcedureConfig `json:"procedures"`
+	}
+
+	// CollectionConfig is settings of one collection
+	CollectionConfig struct {
+		ShardsCount int `json:"shards_count"`
+
+		// default TTL of objects, 0 - storage default TTL
+		DefaultTTL time.Duration `json:"ttl_in_seconds"`
+		// objects w/o expiration settings never expire
+		Timeless bool `json:"timeless"`
+		// timeless objects are rejected
+		NoTimeless bool `json:"no_timeless"`
+
+		// limits of collection, 0 - unlimited
+		MaxKeys        int    `json:"max_keys"`
+		MaxMemory      int64  `json:"maxmemory"`
+		EvictionPolicy string `json:"eviction_policy"`
+	}
+
+	// CollectionUpdate is runtime update of collection settings, shards count couldn't be changed,
+	// nil field keeps current setting, zero field resets it: 0 limit is unlimited, 0 TTL is storage default TTL
+	CollectionUpdate struct {
+		DefaultTTL     *time.Duration `json:"ttl_in_seconds"`
+		Timeless       *bool          `json:"timeless"`
+		NoTimeless     *bool          `json:"no_timeless"`
+		MaxKeys        *int           `json:"max_keys"`
+		MaxMemory      *int64         `json:"maxmemory"`
+		EvictionPolicy *string        `json:"eviction_policy"`
+	}
+
+	// ExpirationConfig is settings of active expiration
+	ExpirationConfig struct {
+		// "index" (default) or "sampling"
+		Strategy string `json:"strategy"`
+
+		// settings of "sampling" strategy
+		SampleSize int           `json:"sample_size"`
+		Threshold  float64       `json:"threshold"`
+		Budget     time.Duration `json:"budget_in_ms"`
+	}
+
+	// ProcedureConfig is limits of every call of stored procedure, procedure holds locks of its keys
+	// while it runs, so limits keep it from stalling refreshing and other requests, 0 - default limit
+	ProcedureConfig struct {
+		MaxSteps int           `json:"max_steps"`
+		Timeout  time.Duration `json:"timeout_in_ms"`
 	}
 
 	ServerConfig struct {
 		Host string         `json:"host"`
 		Port string         `json:"port"`
 		Auth BaseAuthConfig `json:"auth"`
+		// auth of admin endpoints, they aren't served w/o user and pass
+		AdminAuth BaseAuthConfig `json:"admin_auth"`
 
 		ReadTimeout  time.Duration `json:"read_timeout_in_ms"`
 		WriteTimeout time.Duration `json:"write_timeout_in_ms"`
@@ -56,6 +140,93 @@ func New(configPath string) (*Config, error) {
 	return &configuration, nil
 }
 
+// CollectionConfig returns settings of collection by name w storage defaults
+func (s StorageConfig) CollectionConfig(name string) CollectionConfig {
+	collectionConfig := s.Collections[name]
+	if collectionConfig.ShardsCount == 0 {
+		collectionConfig.ShardsCount = s.ShardsCount
+	}
+	if collectionConfig.EvictionPolicy == "" {
+		collectionConfig.EvictionPolicy = s.EvictionPolicy
+	}
+	return collectionConfig
+}
+
+// Merge returns settings w non-empty fields of override
+func (c CollectionConfig) Merge(override CollectionConfig) CollectionConfig {
+	if override.ShardsCount != 0 {
+		c.ShardsCount = override.ShardsCount
+	}
+	if override.DefaultTTL != 0 {
+		c.DefaultTTL = override.DefaultTTL
+	}
+	if override.Timeless || override.NoTimeless {
+		c.Timeless = override.Timeless
+		c.NoTimeless = override.NoTimeless
+	}
+	if override.MaxKeys != 0 {
+		c.MaxKeys = override.MaxKeys
+	}
+	if override.MaxMemory != 0 {
+		c.MaxMemory = override.MaxMemory
+	}
+	if override.EvictionPolicy != "" {
+		c.EvictionPolicy = override.EvictionPolicy
+	}
+	return c
+}
+
+// Apply returns settings w non-nil fields of update, enabled timeless option switches off the opposite one
+// if it isn't set by update
+func (u CollectionUpdate) Apply(c CollectionConfig) CollectionConfig {
+	if u.DefaultTTL != nil {
+		c.DefaultTTL = *u.DefaultTTL
+	}
+	if u.Timeless != nil {
+		c.Timeless = *u.Timeless
+		if c.Timeless && u.NoTimeless == nil {
+			c.NoTimeless = false
+		}
+	}
+	if u.NoTimeless != nil {
+		c.NoTimeless = *u.NoTimeless
+		if c.NoTimeless && u.Timeless == nil {
+			c.Timeless = false
+		}
+	}
+	if u.MaxKeys != nil {
+		c.MaxKeys = *u.MaxKeys
+	}
+	if u.MaxMemory != nil {
+		c.MaxMemory = *u.MaxMemory
+	}
+	if u.EvictionPolicy != nil {
+		c.EvictionPolicy = *u.EvictionPolicy
+	}
+	return c
+}
+
+func (e ExpirationConfig) IsSampling() bool {
+	return e.Strategy == ExpirationSampling
+}
+
+// Limits returns limits w defaults instead of empty ones
+func (p ProcedureConfig) Limits() (maxSteps int, timeout time.Duration) {
+	maxSteps, timeout = p.MaxSteps, p.Timeout*time.Millisecond
+	if maxSteps == 0 {
+		maxSteps = DefaultProcedureMaxSteps
+	}
+	if timeout == 0 {
+		timeout = DefaultProcedureTimeout
+	}
+	return maxSteps, timeout
+}
+
+// HasAdminAuth reports whether admin endpoints have user and pass, so they could be served
+func (s ServerConfig) HasAdminAuth() bool {
+	return s.AdminAuth.User != "" && s.AdminAuth.Pass != ""
+}
+
 func (s ServerConfig) URL() string {
 	return fmt.Sprintf("%s:%s", s.Host, s.Port)
 }
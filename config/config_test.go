@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -34,6 +35,69 @@ func deleteTestConfig() error {
 	return os.Remove(testConfig)
 }
 
+func TestCollectionUpdate_Apply(t *testing.T) {
+	current := CollectionConfig{ShardsCount: 4, DefaultTTL: 10, NoTimeless: true, MaxKeys: 5, MaxMemory: 100, EvictionPolicy: AllKeysLRU}
+
+	tests := []struct {
+		name   string
+		update CollectionUpdate
+		want   CollectionConfig
+	}{
+		{name: "nil fields keep settings", want: current},
+		{
+			name:   "zero fields turn limits off",
+			update: CollectionUpdate{MaxKeys: ptr(0), MaxMemory: ptr(int64(0)), DefaultTTL: ptr(time.Duration(0))},
+			want:   CollectionConfig{ShardsCount: 4, NoTimeless: true, EvictionPolicy: AllKeysLRU},
+		},
+		{
+			name:   "timeless options off",
+			update: CollectionUpdate{NoTimeless: ptr(false)},
+			want:   CollectionConfig{ShardsCount: 4, DefaultTTL: 10, MaxKeys: 5, MaxMemory: 100, EvictionPolicy: AllKeysLRU},
+		},
+		{
+			name:   "timeless switches off no timeless",
+			update: CollectionUpdate{Timeless: ptr(true)},
+			want:   CollectionConfig{ShardsCount: 4, DefaultTTL: 10, Timeless: true, MaxKeys: 5, MaxMemory: 100, EvictionPolicy: AllKeysLRU},
+		},
+		{
+			name:   "eviction policy",
+			update: CollectionUpdate{EvictionPolicy: ptr(NoEviction)},
+			want:   CollectionConfig{ShardsCount: 4, DefaultTTL: 10, NoTimeless: true, MaxKeys: 5, MaxMemory: 100, EvictionPolicy: NoEviction},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, test.update.Apply(current))
+		})
+	}
+}
+
+// ptr returns pointer to value
+func ptr[T any](value T) *T {
+	return &value
+}
+
+func TestServerConfig_HasAdminAuth(t *testing.T) {
+	tests := []struct {
+		name      string
+		adminAuth BaseAuthConfig
+		auth      BaseAuthConfig
+		want      bool
+	}{
+		{name: "admin auth", adminAuth: BaseAuthConfig{User: "admin", Pass: "pass"}, want: true},
+		{name: "empty", want: false},
+		{name: "w/o pass", adminAuth: BaseAuthConfig{User: "admin"}, want: false},
+		{name: "only server auth", auth: BaseAuthConfig{User: "user", Pass: "pass"}, want: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, ServerConfig{Auth: test.auth, AdminAuth: test.adminAuth}.HasAdminAuth())
+		})
+	}
+}
+
 func TestNew(t *testing.T) {
 	tests := []struct {
 		name       string
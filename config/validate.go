@@ -14,6 +14,75 @@ func (s StorageConfig) Validate() error {
 		return errors.ErrEmptyField("refresh_time")
 	case s.MaxCollectionsCount <= 0:
 		return errors.ErrEmptyField("max_collections_count")
+	}
+
+	if err := validateEvictionPolicy(s.EvictionPolicy); err != nil {
+		return err
+	}
+	for _, collection := range s.Collections {
+		if err := collection.Validate(); err != nil {
+			return err
+		}
+	}
+	if err := s.Procedures.Validate(); err != nil {
+		return err
+	}
+	return s.Expiration.Validate()
+}
+
+func (p ProcedureConfig) Validate() error {
+	switch {
+	case p.MaxSteps < 0:
+		return errors.ErrNegativeField("procedures.max_steps")
+	case p.Timeout < 0:
+		return errors.ErrNegativeField("procedures.timeout_in_ms")
+	default:
+		return nil
+	}
+}
+
+func (c CollectionConfig) Validate() error {
+	switch {
+	case c.ShardsCount < 0:
+		return errors.ErrNegativeField("shards_count")
+	case c.DefaultTTL < 0:
+		return errors.ErrNegativeField("ttl_in_seconds")
+	case c.MaxKeys < 0:
+		return errors.ErrNegativeField("max_keys")
+	case c.MaxMemory < 0:
+		return errors.ErrNegativeField("maxmemory")
+	case c.Timeless && c.NoTimeless:
+		return errors.ErrConflictFields("timeless", "no_timeless")
+	default:
+		return validateEvictionPolicy(c.EvictionPolicy)
+	}
+}
+
+func validateEvictionPolicy(policy string) error {
+	switch policy {
+	case "", NoEviction, AllKeysLRU, AllKeysLFU, VolatileLRU, VolatileTTL, VolatileRandom:
+		return nil
+	default:
+		return errors.ErrUnknownValue("eviction_policy", policy)
+	}
+}
+
+func (e ExpirationConfig) Validate() error {
+	switch e.Strategy {
+	case "", ExpirationIndex:
+		return nil
+	case ExpirationSampling:
+	default:
+		return errors.ErrUnknownValue("expiration.strategy", e.Strategy)
+	}
+
+	switch {
+	case e.SampleSize <= 0:
+		return errors.ErrEmptyField("expiration.sample_size")
+	case e.Threshold <= 0:
+		return errors.ErrEmptyField("expiration.threshold")
+	case e.Budget <= 0:
+		return errors.ErrEmptyField("expiration.budget")
 	default:
 		return nil
 	}
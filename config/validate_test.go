@@ -79,6 +79,88 @@ func TestConfig_validation(t *testing.T) {
 			},
 			wantError: errors.ErrEmptyField("refresh_time"),
 		},
+		{
+			name: "StorageConfig: unknown expiration strategy",
+			haveConfig: Config{
+				StorageConfig: StorageConfig{
+					DefaultTTL:          1,
+					MaxCollectionsCount: 1,
+					RefreshTime:         1,
+					Expiration: ExpirationConfig{
+						Strategy: "unknown",
+					},
+				},
+				ServerConfig: ServerConfig{
+					Host:         "host",
+					Port:         "port",
+					ReadTimeout:  1,
+					WriteTimeout: 1,
+				},
+			},
+			wantError: errors.ErrUnknownValue("expiration.strategy", "unknown"),
+		},
+		{
+			name: "StorageConfig: sampling w/o sample size",
+			haveConfig: Config{
+				StorageConfig: StorageConfig{
+					DefaultTTL:          1,
+					MaxCollectionsCount: 1,
+					RefreshTime:         1,
+					Expiration: ExpirationConfig{
+						Strategy:  ExpirationSampling,
+						Threshold: 0.25,
+						Budget:    1,
+					},
+				},
+				ServerConfig: ServerConfig{
+					Host:         "host",
+					Port:         "port",
+					ReadTimeout:  1,
+					WriteTimeout: 1,
+				},
+			},
+			wantError: errors.ErrEmptyField("expiration.sample_size"),
+		},
+		{
+			name: "StorageConfig: negative procedure steps",
+			haveConfig: Config{
+				StorageConfig: StorageConfig{
+					DefaultTTL:          1,
+					MaxCollectionsCount: 1,
+					RefreshTime:         1,
+					Procedures: ProcedureConfig{
+						MaxSteps: -1,
+					},
+				},
+				ServerConfig: ServerConfig{
+					Host:         "host",
+					Port:         "port",
+					ReadTimeout:  1,
+					WriteTimeout: 1,
+				},
+			},
+			wantError: errors.ErrNegativeField("procedures.max_steps"),
+		},
+		{
+			name: "StorageConfig: unknown collection eviction policy",
+			haveConfig: Config{
+				StorageConfig: StorageConfig{
+					DefaultTTL:          1,
+					MaxCollectionsCount: 1,
+					RefreshTime:         1,
+					Collections: map[string]CollectionConfig{
+						"test": {EvictionPolicy: "unknown"},
+					},
+				},
+				ServerConfig: ServerConfig{
+					Host:         "host",
+					Port:         "port",
+					ReadTimeout:  1,
+					WriteTimeout: 1,
+				},
+			},
+			wantError: errors.ErrUnknownValue("eviction_policy", "unknown"),
+		},
 		{
 			name: "ServerConfig: host is empty",
 			haveConfig: Config{
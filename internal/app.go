@@ -46,6 +46,16 @@ func (a *Application) Run() {
 	}
 	r.HandleFunc("/", handlers.BaseAuth(mainHandler, a.config.ServerConfig.Auth))
 
+	// admin endpoints are never served w/o own auth
+	if a.config.ServerConfig.HasAdminAuth() {
+		proceduresHandler := func(writer http.ResponseWriter, request *http.Request) {
+			handlers.ProceduresHandler(writer, request, a.storage)
+		}
+		r.HandleFunc("/procedures", handlers.BaseAuth(proceduresHandler, a.config.ServerConfig.AdminAuth))
+	} else {
+		a.logger.Warn("admin_auth is empty, admin endpoints aren't served")
+	}
+
 	server := &http.Server{
 		Addr:         a.config.ServerConfig.URL(),
 		Handler:      r,
@@ -1,6 +1,7 @@
 package errors
 
 import (
+	stderrors "errors"
 	"fmt"
 	"net/http"
 )
@@ -18,13 +19,134 @@ func ErrNoObject(objectKey string) error {
 	return fmt.Errorf("no object w key: %s", objectKey)
 }
 
+func ErrNotInteger(objectKey string) error {
+	return fmt.Errorf("object w key: %s isn't an integer", objectKey)
+}
+
+func ErrFieldNotInteger(field string) error {
+	return fmt.Errorf("field: %s isn't an integer", field)
+}
+
+func ErrNotFloat(objectKey string) error {
+	return fmt.Errorf("object w key: %s isn't a float", objectKey)
+}
+
+func ErrUnknownCommand(command string) error {
+	return fmt.Errorf("unknown command: %s", command)
+}
+
 func ErrEmptyField(field string) error {
 	return fmt.Errorf("%s is empty", field)
 }
 
+func ErrUnknownValue(field, value string) error {
+	return fmt.Errorf("unknown %s: %s", field, value)
+}
+
+func ErrInvalidETag(etag string) error {
+	return fmt.Errorf("invalid etag: %s", etag)
+}
+
+func ErrWeakETag(etag string) error {
+	return fmt.Errorf("weak etag couldn't be used in If-Match: %s", etag)
+}
+
+func ErrNegativeField(field string) error {
+	return fmt.Errorf("%s is negative", field)
+}
+
+func ErrConflictFields(first, second string) error {
+	return fmt.Errorf("%s and %s couldn't be used together", first, second)
+}
+
+func ErrInvalidScoreBound(bound string) error {
+	return fmt.Errorf("invalid score bound: %s", bound)
+}
+
+func ErrInvalidStreamID(id string) error {
+	return fmt.Errorf("invalid stream id: %s", id)
+}
+
+func ErrGroupExists(group string) error {
+	return fmt.Errorf("consumer group already exists: %s", group)
+}
+
+func ErrNoGroup(group string) error {
+	return fmt.Errorf("no consumer group: %s", group)
+}
+
+func ErrInvalidCoordinates(longitude, latitude float64) error {
+	return fmt.Errorf("invalid longitude, latitude pair: %g, %g", longitude, latitude)
+}
+
+func ErrNoMember(member string) error {
+	return fmt.Errorf("no member: %s", member)
+}
+
+func ErrTransactionAborted(operation int, err error) error {
+	return fmt.Errorf("transaction aborted by operation %d: %w", operation, err)
+}
+
+// ErrWatchFailed - watched object was changed, deleted, created or expired
+func ErrWatchFailed(collectionName, objectKey string) error {
+	return fmt.Errorf("%w: %s in collection %s", ErrWatchedObjectChanged, objectKey, collectionName)
+}
+
+// ErrObjectTooLarge - object is larger than memory limit, it's ErrOutOfMemory too
+func ErrObjectTooLarge(size int64) error {
+	return fmt.Errorf("%w: object of %d bytes is larger than memory limit", ErrOutOfMemory, size)
+}
+
+func ErrNoProcedure(name string) error {
+	return fmt.Errorf("no procedure w name: %s", name)
+}
+
+// ErrProcedureFailed - procedure was failed by its fail step
+func ErrProcedureFailed(message string) error {
+	return fmt.Errorf("procedure failed: %s", message)
+}
+
+func ErrProcedureAborted(step int, err error) error {
+	return fmt.Errorf("procedure aborted at step %d: %w", step, err)
+}
+
+func ErrInvalidStep(step int, err error) error {
+	return fmt.Errorf("invalid step %d: %w", step, err)
+}
+
+func ErrWrongKeysCount(expected, actual int) error {
+	return fmt.Errorf("procedure expects %d keys, got %d", expected, actual)
+}
+
+func ErrNoArgument(index int) error {
+	return fmt.Errorf("no argument w index: %d", index)
+}
+
+func ErrInvalidCursor(cursor string) error {
+	return fmt.Errorf("invalid cursor: %s", cursor)
+}
+
+func ErrInvalidPattern(pattern string) error {
+	return fmt.Errorf("invalid pattern: %s", pattern)
+}
+
 var (
 	ErrDeleteDefaultCollection = fmt.Errorf("couldn't delete default collection")
 	ErrMaxCollectionsCount     = fmt.Errorf("too many collections")
+	ErrOutOfMemory             = fmt.Errorf("not enough memory for object")
+	ErrMaxKeys                 = fmt.Errorf("too many objects in collection")
+	ErrTimelessForbidden       = fmt.Errorf("timeless objects are forbidden in collection")
+	ErrVersionConflict         = fmt.Errorf("object version doesn't match expected version")
+	ErrIfMatchManyObjects      = fmt.Errorf("If-Match could be used only for request w single object")
+	ErrWatchedObjectChanged    = fmt.Errorf("transaction aborted, watched object was changed")
+	ErrIncrementOverflow       = fmt.Errorf("increment would overflow")
+	ErrIncrementNaN            = fmt.Errorf("increment would produce NaN or Infinity")
+	ErrWrongType               = fmt.Errorf("operation against object holding the wrong type of value")
+	ErrStreamIDTooSmall        = fmt.Errorf("id is equal or smaller than id of the last entry of stream")
+	ErrBitOffsetOutOfRange     = fmt.Errorf("bit offset is out of range")
+	ErrBitOpNotSingleKey       = fmt.Errorf("not operation must have the only source key")
+	ErrProcedureStepsLimit     = fmt.Errorf("procedure exceeded steps limit")
+	ErrProcedureTimeout        = fmt.Errorf("procedure exceeded time limit")
 )
 
 // error struct for response
@@ -47,17 +169,31 @@ func ErrMsgUnmarshalBody(err error) Error {
 	}
 }
 
-func ErrMsgMarshalCollection(err error) Error {
+func ErrMsgUnknownType(type_ string) Error {
 	return Error{
-		Message: fmt.Sprintf("couldn't marshal collection w err: %s", err.Error()),
-		Code:    http.StatusInternalServerError,
+		Message: fmt.Sprintf("unknown type: %s", type_),
+		Code:    http.StatusBadRequest,
 	}
 }
 
-func ErrMsgUnknownType(type_ string) Error {
+func ErrMsgMethodNotAllowed(method string) Error {
 	return Error{
-		Message: fmt.Sprintf("unknown type: %s", type_),
-		Code:    http.StatusBadRequest,
+		Message: fmt.Sprintf("method not allowed: %s", method),
+		Code:    http.StatusMethodNotAllowed,
+	}
+}
+
+// CodeByError returns http code of error or wrapped error or default code if error has no own code
+func CodeByError(err error, defaultCode int) int {
+	switch {
+	case stderrors.Is(err, ErrOutOfMemory), stderrors.Is(err, ErrMaxKeys):
+		return http.StatusInsufficientStorage
+	case stderrors.Is(err, ErrVersionConflict):
+		return http.StatusConflict
+	case stderrors.Is(err, ErrWatchedObjectChanged):
+		return http.StatusPreconditionFailed
+	default:
+		return defaultCode
 	}
 }
 
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+// bitmap commands
+const (
+	CommandSetBit   = "setbit"
+	CommandGetBit   = "getbit"
+	CommandBitCount = "bitcount"
+	CommandBitPos   = "bitpos"
+	CommandBitOp    = "bitop"
+)
+
+type (
+	// BitmapRequest - atomic operation on bits of binary object, response data is bit, count, position or length
+	BitmapRequest struct {
+		Collection string `json:"collection"`
+		Key        string `json:"key"`
+		Command    string `json:"command"`
+
+		// offset of bit for setbit, getbit
+		Offset uint64 `json:"offset"`
+		// bit 0 or 1 for setbit, bitpos
+		Bit int `json:"bit"`
+		// inclusive indexes of range for bitcount, bitpos, negative index is counted from the end,
+		// empty end is the end of object
+		Start int  `json:"start"`
+		End   *int `json:"end"`
+		// "byte" (default) or "bit" unit of range indexes
+		Unit object.BitUnit `json:"unit"`
+		// "and", "or", "xor" or "not" for bitop, result is stored to key of request
+		Operation object.BitOperation `json:"operation"`
+		// source keys for bitop, empty collection of key is collection of request
+		Keys []storage.ObjectRef `json:"keys"`
+
+		// expiration settings of new object for setbit or result of bitop
+		Expiration object.RequestSettings `json:"expiration"`
+	}
+)
+
+func (r BitmapRequest) ProcessCommand(s storage.Storage) Response {
+	var (
+		response = Response{Success: true}
+		result   int
+		err      error
+	)
+	switch r.Command {
+	case CommandSetBit:
+		var bit, previous bool
+		bit, err = r.bit()
+		if err == nil {
+			previous, err = storage.SetBit(s, r.Collection, r.Key, r.Offset, bit, r.Expiration)
+		}
+		response.Data = bitData(previous)
+	case CommandGetBit:
+		var bit bool
+		bit, err = storage.GetBit(s, r.Collection, r.Key, r.Offset)
+		response.Data = bitData(bit)
+	case CommandBitCount:
+		end := -1
+		if r.End != nil {
+			end = *r.End
+		}
+		result, err = storage.BitCount(s, r.Collection, r.Key, r.Start, end, r.Unit)
+		response.Data = strconv.AppendInt(nil, int64(result), 10)
+	case CommandBitPos:
+		var bit bool
+		bit, err = r.bit()
+		if err == nil {
+			result, err = storage.BitPos(s, r.Collection, r.Key, bit, r.Start, r.End, r.Unit)
+		}
+		response.Data = strconv.AppendInt(nil, int64(result), 10)
+	case CommandBitOp:
+		target := storage.ObjectRef{Collection: r.Collection, Key: r.Key}
+		result, err = storage.BitOp(s, r.Operation, target, requestRefs(r.Collection, r.Keys), r.Expiration)
+		response.Data = strconv.AppendInt(nil, int64(result), 10)
+	default:
+		err = errors.ErrUnknownCommand(r.Command)
+	}
+
+	if err != nil {
+		errMsg := errors.ErrMsgByError(err, errors.CodeByError(err, http.StatusBadRequest))
+		return ResponseByError(errMsg)
+	}
+	return response
+}
+
+func (r BitmapRequest) bit() (bool, error) {
+	switch r.Bit {
+	case 0, 1:
+		return r.Bit == 1, nil
+	default:
+		return false, errors.ErrUnknownValue("bit", strconv.Itoa(r.Bit))
+	}
+}
+
+func bitData(bit bool) []byte {
+	if bit {
+		return []byte("1")
+	}
+	return []byte("0")
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+// counter commands
+const (
+	CommandIncr        = "incr"
+	CommandDecr        = "decr"
+	CommandIncrBy      = "incrby"
+	CommandDecrBy      = "decrby"
+	CommandIncrByFloat = "incrbyfloat"
+)
+
+type (
+	// CounterRequest - atomic increment of numeric object, response data is new value
+	CounterRequest struct {
+		Collection string `json:"collection"`
+		Key        string `json:"key"`
+		Command    string `json:"command"`
+
+		// increment for incrby, decrby
+		Increment int64 `json:"increment"`
+		// increment for incrbyfloat
+		FloatIncrement float64 `json:"float_increment"`
+
+		// expiration settings, if empty existing object keeps its expiration
+		Expiration object.RequestSettings `json:"expiration"`
+	}
+)
+
+func (r CounterRequest) ProcessCommand(s storage.Storage) Response {
+	var (
+		obj object.Object
+		err error
+	)
+	switch r.Command {
+	case CommandIncr:
+		obj, err = storage.IncrementObject(s, r.Collection, r.Key, 1, r.Expiration)
+	case CommandDecr:
+		obj, err = storage.IncrementObject(s, r.Collection, r.Key, -1, r.Expiration)
+	case CommandIncrBy:
+		obj, err = storage.IncrementObject(s, r.Collection, r.Key, r.Increment, r.Expiration)
+	case CommandDecrBy:
+		if r.Increment == math.MinInt64 {
+			err = errors.ErrIncrementOverflow
+			break
+		}
+		obj, err = storage.IncrementObject(s, r.Collection, r.Key, -r.Increment, r.Expiration)
+	case CommandIncrByFloat:
+		obj, err = storage.IncrementFloatObject(s, r.Collection, r.Key, r.FloatIncrement, r.Expiration)
+	default:
+		err = errors.ErrUnknownCommand(r.Command)
+	}
+
+	if err != nil {
+		errMsg := errors.ErrMsgByError(err, errors.CodeByError(err, http.StatusBadRequest))
+		return ResponseByError(errMsg)
+	}
+
+	return Response{
+		Data:    obj.Binary(),
+		Success: true,
+		Version: obj.Version(),
+	}
+}
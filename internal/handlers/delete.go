@@ -12,10 +12,12 @@ type (
 	DeleteRequest struct {
 		Collection string   `json:"collection"`
 		Keys       []string `json:"keys"`
+		// expected versions of objects by keys
+		Versions map[string]uint64 `json:"versions"`
 	}
 )
 
-func (r DeleteRequest) ProcessCollection(s storage.Storage) Response {
+func (r DeleteRequest) ProcessCollection(s storage.Storage) DataCode {
 	return deleteCollectionResponse(r.Collection, s)
 }
 
@@ -23,7 +25,7 @@ func (r DeleteRequest) ProcessObjects(s storage.Storage) []Response {
 	var responses = make([]Response, 0, len(r.Keys))
 
 	for _, key := range r.Keys {
-		responsePart := deleteObjectResponse(r.Collection, key, s)
+		responsePart := deleteObjectResponse(r.Collection, key, r.Versions[key], s)
 		responses = append(responses, responsePart)
 	}
 
@@ -41,13 +43,30 @@ func deleteCollectionResponse(name string, s storage.Storage) Response {
 	}
 }
 
-func deleteObjectResponse(name, key string, s storage.Storage) Response {
-	err := storage.DeleteObject(s, name, key)
+func deleteObjectResponse(name, key string, version uint64, s storage.Storage) Response {
+	err := storage.DeleteObject(s, name, key, version)
 	if err != nil {
-		errMsg := errors.ErrMsgByError(err, http.StatusBadRequest)
+		errMsg := errors.ErrMsgByError(err, errors.CodeByError(err, http.StatusBadRequest))
 		return ResponseByError(errMsg)
 	}
 	return Response{
 		Success: true,
 	}
 }
+
+// ExpectVersion set expected version for the only key if it's w/o version
+func (r *DeleteRequest) ExpectVersion(version uint64) error {
+	if len(r.Keys) > 1 {
+		return errors.ErrIfMatchManyObjects
+	}
+
+	if r.Versions == nil {
+		r.Versions = make(map[string]uint64, len(r.Keys))
+	}
+	for _, key := range r.Keys {
+		if _, ok := r.Versions[key]; !ok {
+			r.Versions[key] = version
+		}
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+type (
+	// ExportedObject - object of GET collection response
+	ExportedObject struct {
+		Key  string      `json:"key"`
+		Type object.Type `json:"type"`
+		// data of string object or json of data type value
+		Data []byte `json:"data,omitempty"`
+		// remaining TTL in seconds, -1 for timeless object
+		TTL     int64  `json:"ttl"`
+		Version uint64 `json:"version"`
+	}
+
+	// collectionExport - GET collection response, objects are written page by page,
+	// so big collection isn't built in memory
+	collectionExport struct {
+		request GetRequest
+		storage storage.Storage
+	}
+)
+
+// exportPrefix is beginning of successful response before objects
+var exportPrefix = func() string {
+	data, _ := json.Marshal(Response{Success: true})
+	return strings.TrimSuffix(string(data), "}") + `,"objects":[`
+}()
+
+func (e collectionExport) DataAndCode() ([]byte, int) {
+	var (
+		buffer bytes.Buffer
+		code   int
+	)
+	e.export(&buffer, func(responseCode int) { code = responseCode })
+	return buffer.Bytes(), code
+}
+
+func (e collectionExport) Stream(w http.ResponseWriter) {
+	e.export(w, w.WriteHeader)
+}
+
+// export writes response, writeHeader is called w code before body
+func (e collectionExport) export(w io.Writer, writeHeader func(code int)) {
+	var written int
+	begin := func() error {
+		writeHeader(http.StatusOK)
+		_, err := io.WriteString(w, exportPrefix)
+		return err
+	}
+
+	next, err := storage.ExportCollection(e.storage, e.request.Collection, e.request.Cursor, e.request.Count, func(entry storage.Entry) error {
+		separator := ","
+		if written == 0 {
+			if err := begin(); err != nil {
+				return err
+			}
+			separator = ""
+		}
+
+		data, err := json.Marshal(e.exportedObject(entry))
+		if err != nil {
+			return err
+		}
+		written++
+		_, err = io.WriteString(w, separator+string(data))
+		return err
+	})
+	if err != nil {
+		// error after the first object breaks body, client gets invalid json
+		if written == 0 {
+			errMsg := errors.ErrMsgByError(err, errors.CodeByError(err, http.StatusBadRequest))
+			data, code := ResponseByError(errMsg).DataAndCode()
+			writeHeader(code)
+			w.Write(data)
+		}
+		return
+	}
+
+	if written == 0 && begin() != nil {
+		return
+	}
+	suffix := "]"
+	if next != "" {
+		cursor, _ := json.Marshal(next)
+		suffix += `,"cursor":` + string(cursor)
+	}
+	io.WriteString(w, suffix+"}")
+}
+
+func (e collectionExport) exportedObject(entry storage.Entry) ExportedObject {
+	exported := ExportedObject{
+		Key:     entry.Key,
+		Type:    entry.Object.Type(),
+		TTL:     ttlSeconds(entry.Object.TTL()),
+		Version: entry.Object.Version(),
+	}
+	if !e.request.KeysOnly {
+		exported.Data = entry.Object.Binary()
+	}
+	return exported
+}
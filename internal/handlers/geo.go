@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+// geo commands
+const (
+	CommandGeoAdd    = "geoadd"
+	CommandGeoPos    = "geopos"
+	CommandGeoDist   = "geodist"
+	CommandGeoSearch = "geosearch"
+)
+
+type (
+	// GeoRequest - operation on geo members of sorted set, response data is count of added members or distance,
+	// response positions are locations of members, response members w distances and positions are found members
+	GeoRequest struct {
+		Collection string `json:"collection"`
+		Key        string `json:"key"`
+		Command    string `json:"command"`
+
+		// locations of members for geoadd
+		Locations map[string]object.GeoPoint `json:"locations"`
+		// only add new members for geoadd
+		NX bool `json:"nx"`
+		// only update existing members for geoadd
+		XX bool `json:"xx"`
+		// members for geopos
+		Members []string `json:"members"`
+		// members for geodist
+		From string `json:"from"`
+		To   string `json:"to"`
+		// center of area for geosearch, member or location
+		Member string          `json:"member"`
+		Center object.GeoPoint `json:"center"`
+		// radius of circle area or width and height of box area for geosearch
+		Radius float64 `json:"radius"`
+		Width  float64 `json:"width"`
+		Height float64 `json:"height"`
+		// "m" (default), "km", "mi" or "ft" unit of distances
+		Unit object.GeoUnit `json:"unit"`
+		// max count of members for geosearch, 0 - all
+		Count int `json:"count"`
+		// the farthest members first for geosearch
+		Desc bool `json:"desc"`
+
+		// expiration settings of new sorted set
+		Expiration object.RequestSettings `json:"expiration"`
+	}
+)
+
+func (r GeoRequest) ProcessCommand(s storage.Storage) Response {
+	var (
+		response = Response{Success: true}
+		err      error
+	)
+	switch r.Command {
+	case CommandGeoAdd:
+		var added int
+		options := object.ZAddOptions{NX: r.NX, XX: r.XX}
+		added, err = storage.GeoAdd(s, r.Collection, r.Key, r.Locations, options, r.Expiration)
+		response.Data = strconv.AppendInt(nil, int64(added), 10)
+	case CommandGeoPos:
+		response.Positions, err = storage.GeoPos(s, r.Collection, r.Key, r.Members)
+	case CommandGeoDist:
+		distance, ok, distErr := storage.GeoDist(s, r.Collection, r.Key, r.From, r.To, r.Unit)
+		if ok {
+			response.Data = strconv.AppendFloat(nil, distance, 'f', 4, 64)
+		}
+		err = distErr
+	case CommandGeoSearch:
+		query := object.GeoQuery{Center: r.Center, Radius: r.Radius, Width: r.Width, Height: r.Height}
+		var results []object.GeoResult
+		results, err = storage.GeoSearch(s, r.Collection, r.Key, r.Member, query, r.Unit, r.Count, r.Desc)
+		for _, result := range results {
+			point := result.Point
+			response.Members = append(response.Members, result.Member)
+			response.Distances = append(response.Distances, result.Distance)
+			response.Positions = append(response.Positions, &point)
+		}
+	default:
+		err = errors.ErrUnknownCommand(r.Command)
+	}
+
+	if err != nil {
+		errMsg := errors.ErrMsgByError(err, errors.CodeByError(err, http.StatusBadRequest))
+		return ResponseByError(errMsg)
+	}
+	return response
+}
@@ -13,11 +13,25 @@ type (
 	GetRequest struct {
 		Collection string   `json:"collection"`
 		Keys       []string `json:"keys"`
+
+		// pagination of collection objects, 0 count - all objects
+		Cursor string `json:"cursor"`
+		Count  int    `json:"count"`
+		// collection objects w/o data
+		KeysOnly bool `json:"keys_only"`
 	}
 )
 
-func (r GetRequest) ProcessCollection(s storage.Storage) Response {
-	return getCollectionResponse(r.Collection, s)
+// ProcessCollection returns objects of collection which are streamed to client
+func (r GetRequest) ProcessCollection(s storage.Storage) DataCode {
+	return collectionExport{
+		request: r,
+		storage: s,
+	}
+}
+
+func (r GetRequest) ProcessSettings(s storage.Storage) Response {
+	return getSettingsResponse(r.Collection, s)
 }
 
 func (r GetRequest) ProcessObjects(s storage.Storage) []Response {
@@ -31,16 +45,16 @@ func (r GetRequest) ProcessObjects(s storage.Storage) []Response {
 	return responses
 }
 
-func getCollectionResponse(name string, s storage.Storage) Response {
+func getSettingsResponse(name string, s storage.Storage) Response {
 	collection, err := s.GetCollection(name)
 	if err != nil {
 		errMsg := errors.ErrMsgByError(err, http.StatusBadRequest)
 		return ResponseByError(errMsg)
 	}
 
-	data, err := json.Marshal(collection)
+	data, err := json.Marshal(collection.Settings())
 	if err != nil {
-		errMsg := errors.ErrMsgMarshalCollection(err)
+		errMsg := errors.ErrMsgByError(err, http.StatusInternalServerError)
 		return ResponseByError(errMsg)
 	}
 
@@ -60,5 +74,6 @@ func getObjectResponse(collectionName, key string, s storage.Storage) Response {
 	return Response{
 		Data:    object.Binary(),
 		Success: true,
+		Version: object.Version(),
 	}
 }
@@ -2,15 +2,63 @@ package handlers
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
 
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
 	"github.com/mustthink/go-storage-like-redis/internal/storage"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
 )
 
 func Handler(w http.ResponseWriter, r *http.Request, s storage.Storage) {
 	request := RequestByMethod(r.Method)
 	request.readRequestBody(r.Body)
-	data, code := request.getResponse(s)
 
+	var response DataCode
+	if ifMatch := r.Header.Get("If-Match"); ifMatch != "" {
+		if err := request.expectETag(ifMatch); err != nil {
+			errMsg := errors.ErrMsgByError(err, http.StatusBadRequest)
+			response = ResponseByError(errMsg)
+		}
+	}
+
+	if response == nil {
+		response = request.getResponse(s)
+	}
+
+	if versioned, ok := response.(Versioned); ok && versioned.ObjectVersion() != 0 {
+		w.Header().Set("ETag", formatETag(versioned.ObjectVersion()))
+	}
+
+	if streamer, ok := response.(Streamer); ok {
+		streamer.Stream(w)
+		return
+	}
+
+	data, code := response.DataAndCode()
 	w.WriteHeader(code)
 	w.Write(data)
 }
+
+// ETag of object is its version in quotes
+func formatETag(version uint64) string {
+	return strconv.Quote(strconv.FormatUint(version, 10))
+}
+
+// parseETag returns version of If-Match etag, "*" is any existing object, weak etags aren't allowed
+func parseETag(etag string) (uint64, error) {
+	switch {
+	case etag == "*":
+		return object.VersionExists, nil
+	case strings.HasPrefix(etag, "W/"):
+		return 0, errors.ErrWeakETag(etag)
+	case len(etag) < 2 || etag[0] != '"' || etag[len(etag)-1] != '"':
+		return 0, errors.ErrInvalidETag(etag)
+	}
+
+	version, err := strconv.ParseUint(etag[1:len(etag)-1], 10, 64)
+	if err != nil || version == 0 || version == object.VersionExists {
+		return 0, errors.ErrInvalidETag(etag)
+	}
+	return version, nil
+}
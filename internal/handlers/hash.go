@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+// hash commands
+const (
+	CommandHSet    = "hset"
+	CommandHGet    = "hget"
+	CommandHMGet   = "hmget"
+	CommandHDel    = "hdel"
+	CommandHGetAll = "hgetall"
+	CommandHIncrBy = "hincrby"
+	CommandHExists = "hexists"
+)
+
+type (
+	// HashRequest - atomic operation on hash object, response data is count or value of field,
+	// response values are values of fields for hmget, response fields are fields w values for hgetall
+	HashRequest struct {
+		Collection string `json:"collection"`
+		Key        string `json:"key"`
+		Command    string `json:"command"`
+
+		// field for hget, hincrby, hexists
+		Field string `json:"field"`
+		// fields for hmget, hdel
+		Fields []string `json:"fields"`
+		// values of fields for hset
+		Values map[string][]byte `json:"values"`
+		// increment for hincrby
+		Increment int64 `json:"increment"`
+
+		// expiration settings of new hash
+		Expiration object.RequestSettings `json:"expiration"`
+	}
+)
+
+func (r HashRequest) ProcessCommand(s storage.Storage) Response {
+	var (
+		response = Response{Success: true}
+		count    int
+		err      error
+	)
+	switch r.Command {
+	case CommandHSet:
+		count, err = storage.HashSet(s, r.Collection, r.Key, r.Values, r.Expiration)
+		response.Data = strconv.AppendInt(nil, int64(count), 10)
+	case CommandHGet:
+		response.Data, err = storage.HashGet(s, r.Collection, r.Key, r.Field)
+	case CommandHMGet:
+		response.Values, err = storage.HashMultiGet(s, r.Collection, r.Key, r.Fields)
+	case CommandHDel:
+		count, err = storage.HashDelete(s, r.Collection, r.Key, r.Fields)
+		response.Data = strconv.AppendInt(nil, int64(count), 10)
+	case CommandHGetAll:
+		response.Fields, err = storage.HashGetAll(s, r.Collection, r.Key)
+	case CommandHIncrBy:
+		response.Data, err = storage.HashIncrement(s, r.Collection, r.Key, r.Field, r.Increment, r.Expiration)
+	case CommandHExists:
+		var exists bool
+		exists, err = storage.HashExists(s, r.Collection, r.Key, r.Field)
+		response.Data = []byte(strconv.FormatBool(exists))
+	default:
+		err = errors.ErrUnknownCommand(r.Command)
+	}
+
+	if err != nil {
+		errMsg := errors.ErrMsgByError(err, errors.CodeByError(err, http.StatusBadRequest))
+		return ResponseByError(errMsg)
+	}
+	return response
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+// hyperloglog commands
+const (
+	CommandPFAdd   = "pfadd"
+	CommandPFCount = "pfcount"
+	CommandPFMerge = "pfmerge"
+)
+
+type (
+	// HyperLogLogRequest - cardinality estimation by hyperloglog object,
+	// response data is "1"/"0" whether estimated count could be changed or estimated count
+	HyperLogLogRequest struct {
+		Collection string `json:"collection"`
+		Key        string `json:"key"`
+		Command    string `json:"command"`
+
+		// elements for pfadd
+		Elements [][]byte `json:"elements"`
+		// keys of hyperloglogs for pfcount instead of key of request or sources for pfmerge,
+		// empty collection of key is collection of request
+		Keys []storage.ObjectRef `json:"keys"`
+
+		// expiration settings of new hyperloglog
+		Expiration object.RequestSettings `json:"expiration"`
+	}
+)
+
+func (r HyperLogLogRequest) ProcessCommand(s storage.Storage) Response {
+	var (
+		response = Response{Success: true}
+		err      error
+	)
+	switch r.Command {
+	case CommandPFAdd:
+		var changed bool
+		changed, err = storage.HyperLogLogAdd(s, r.Collection, r.Key, r.Elements, r.Expiration)
+		response.Data = bitData(changed)
+	case CommandPFCount:
+		refs := requestRefs(r.Collection, r.Keys)
+		if len(refs) == 0 {
+			refs = []storage.ObjectRef{{Collection: r.Collection, Key: r.Key}}
+		}
+
+		var count uint64
+		count, err = storage.HyperLogLogCount(s, refs)
+		response.Data = strconv.AppendUint(nil, count, 10)
+	case CommandPFMerge:
+		target := storage.ObjectRef{Collection: r.Collection, Key: r.Key}
+		err = storage.HyperLogLogMerge(s, target, requestRefs(r.Collection, r.Keys), r.Expiration)
+	default:
+		err = errors.ErrUnknownCommand(r.Command)
+	}
+
+	if err != nil {
+		errMsg := errors.ErrMsgByError(err, errors.CodeByError(err, http.StatusBadRequest))
+		return ResponseByError(errMsg)
+	}
+	return response
+}
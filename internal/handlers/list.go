@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+// list commands
+const (
+	CommandLPush  = "lpush"
+	CommandRPush  = "rpush"
+	CommandLPop   = "lpop"
+	CommandRPop   = "rpop"
+	CommandLRange = "lrange"
+	CommandLTrim  = "ltrim"
+	CommandLLen   = "llen"
+	CommandLIndex = "lindex"
+	CommandLRem   = "lrem"
+)
+
+type (
+	// ListRequest - atomic operation on list object,
+	// response data is length or value, response values are values of list
+	ListRequest struct {
+		Collection string `json:"collection"`
+		Key        string `json:"key"`
+		Command    string `json:"command"`
+
+		// values for lpush, rpush
+		Values [][]byte `json:"values"`
+		// value for lrem
+		Value []byte `json:"value"`
+		// count of values for lpop, rpop, default 1, and lrem
+		Count int `json:"count"`
+		// inclusive indexes for lrange, ltrim, negative index is counted from the tail
+		Start int `json:"start"`
+		Stop  int `json:"stop"`
+		// index for lindex
+		Index int `json:"index"`
+
+		// expiration settings of new list
+		Expiration object.RequestSettings `json:"expiration"`
+	}
+)
+
+func (r ListRequest) ProcessCommand(s storage.Storage) Response {
+	var (
+		response = Response{Success: true}
+		length   int
+		err      error
+	)
+	switch r.Command {
+	case CommandLPush, CommandRPush:
+		length, err = storage.ListPush(s, r.Collection, r.Key, r.Command == CommandLPush, r.Values, r.Expiration)
+		response.Data = strconv.AppendInt(nil, int64(length), 10)
+	case CommandLPop, CommandRPop:
+		count := r.Count
+		if count == 0 {
+			count = 1
+		}
+		response.Values, err = storage.ListPop(s, r.Collection, r.Key, r.Command == CommandLPop, count)
+	case CommandLRange:
+		response.Values, err = storage.ListRange(s, r.Collection, r.Key, r.Start, r.Stop)
+	case CommandLTrim:
+		err = storage.ListTrim(s, r.Collection, r.Key, r.Start, r.Stop)
+	case CommandLLen:
+		length, err = storage.ListLen(s, r.Collection, r.Key)
+		response.Data = strconv.AppendInt(nil, int64(length), 10)
+	case CommandLIndex:
+		response.Data, err = storage.ListIndex(s, r.Collection, r.Key, r.Index)
+	case CommandLRem:
+		length, err = storage.ListRemove(s, r.Collection, r.Key, r.Count, r.Value)
+		response.Data = strconv.AppendInt(nil, int64(length), 10)
+	default:
+		err = errors.ErrUnknownCommand(r.Command)
+	}
+
+	if err != nil {
+		errMsg := errors.ErrMsgByError(err, errors.CodeByError(err, http.StatusBadRequest))
+		return ResponseByError(errMsg)
+	}
+	return response
+}
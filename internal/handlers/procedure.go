@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage"
+)
+
+type (
+	// ProcedureRequest - call of registered procedure, response data is result of procedure
+	ProcedureRequest struct {
+		Collection string `json:"collection"`
+		Name       string `json:"name"`
+		// keys of call, empty collection of key is collection of request
+		Keys []storage.ObjectRef `json:"keys"`
+		Args [][]byte            `json:"args"`
+	}
+
+	// ProceduresRequest - admin request of procedures registry:
+	// POST, PUT registers procedure, GET returns procedure by name or all procedures w/o name, DELETE deletes procedure
+	ProceduresRequest struct {
+		Name      string            `json:"name"`
+		Procedure storage.Procedure `json:"procedure"`
+	}
+)
+
+func (r ProcedureRequest) ProcessCommand(s storage.Storage) Response {
+	result, err := storage.CallProcedure(s, r.Name, requestRefs(r.Collection, r.Keys), r.Args)
+	if err != nil {
+		errMsg := errors.ErrMsgByError(err, errors.CodeByError(err, http.StatusBadRequest))
+		return ResponseByError(errMsg)
+	}
+	return Response{Success: true, Data: result}
+}
+
+// ProceduresHandler - admin handler of procedures registry
+func ProceduresHandler(w http.ResponseWriter, r *http.Request, s storage.Storage) {
+	var request ProceduresRequest
+	response := request.readRequestBody(r.Body)
+	if response.Error.Code == 0 {
+		response = request.process(r.Method, s)
+	}
+
+	data, code := response.DataAndCode()
+	w.WriteHeader(code)
+	w.Write(data)
+}
+
+// readRequestBody - empty body is request w/o name
+func (r *ProceduresRequest) readRequestBody(requestBody io.ReadCloser) Response {
+	body, err := io.ReadAll(requestBody)
+	if err != nil {
+		return ResponseByError(errors.ErrMsgReadBody(err))
+	}
+	if len(body) == 0 {
+		return Response{}
+	}
+
+	if err := json.Unmarshal(body, r); err != nil {
+		return ResponseByError(errors.ErrMsgUnmarshalBody(err))
+	}
+	return Response{}
+}
+
+func (r ProceduresRequest) process(method string, s storage.Storage) Response {
+	var (
+		response = Response{Success: true}
+		err      error
+	)
+	switch method {
+	case http.MethodPost, http.MethodPut:
+		err = s.RegisterProcedure(r.Name, r.Procedure)
+	case http.MethodGet:
+		if r.Name == "" {
+			response.Procedures = s.Procedures()
+			break
+		}
+
+		var procedure storage.Procedure
+		procedure, err = s.GetProcedure(r.Name)
+		response.Procedures = map[string]storage.Procedure{r.Name: procedure}
+	case http.MethodDelete:
+		err = s.DeleteProcedure(r.Name)
+	default:
+		return ResponseByError(errors.ErrMsgMethodNotAllowed(method))
+	}
+
+	if err != nil {
+		errMsg := errors.ErrMsgByError(err, errors.CodeByError(err, http.StatusBadRequest))
+		return ResponseByError(errMsg)
+	}
+	return response
+}
@@ -13,21 +13,73 @@ const (
 	// types
 	TypeCollection = "collection"
 	TypeObject     = "object"
+	TypeStats      = "stats"
+	TypeSettings   = "settings"
+
+	// types of commands
+	TypeCounter     = "counter"
+	TypeTTL         = "ttl"
+	TypeScan        = "scan"
+	TypeList        = "list"
+	TypeHash        = "hash"
+	TypeSet         = "set"
+	TypeZSet        = "zset"
+	TypeStream      = "stream"
+	TypeBitmap      = "bitmap"
+	TypeHLL         = "hyperloglog"
+	TypeGeo         = "geo"
+	TypeTransaction = "transaction"
+	TypeProcedure   = "procedure"
 )
 
 type (
 	RequestProcessor interface {
-		ProcessCollection(s storage.Storage) Response
+		ProcessCollection(s storage.Storage) DataCode
 		ProcessObjects(s storage.Storage) []Response
 	}
 
+	// ConditionalRequest - objects request w expected version of objects
+	ConditionalRequest interface {
+		// ExpectVersion sets expected version of the only object of request
+		ExpectVersion(version uint64) error
+	}
+
+	// SettingsProcessor - processor of collection settings requests
+	SettingsProcessor interface {
+		ProcessSettings(s storage.Storage) Response
+	}
+
+	// CommandProcessor - processor of data types commands, they don't depend on request method
+	CommandProcessor interface {
+		ProcessCommand(s storage.Storage) Response
+	}
+
 	// Request - struct of requests
 	Request struct {
 		Type string `json:"type"`
 		RequestProcessor
+
+		command CommandProcessor
 	}
 )
 
+// commandsByType - constructors of command processors by request type
+var commandsByType = map[string]func() CommandProcessor{
+	TypeCounter:     func() CommandProcessor { return &CounterRequest{} },
+	TypeTTL:         func() CommandProcessor { return &TTLRequest{} },
+	TypeScan:        func() CommandProcessor { return &ScanRequest{} },
+	TypeList:        func() CommandProcessor { return &ListRequest{} },
+	TypeHash:        func() CommandProcessor { return &HashRequest{} },
+	TypeSet:         func() CommandProcessor { return &SetRequest{} },
+	TypeZSet:        func() CommandProcessor { return &ZSetRequest{} },
+	TypeStream:      func() CommandProcessor { return &StreamRequest{} },
+	TypeBitmap:      func() CommandProcessor { return &BitmapRequest{} },
+	TypeHLL:         func() CommandProcessor { return &HyperLogLogRequest{} },
+	TypeGeo:         func() CommandProcessor { return &GeoRequest{} },
+	TypeTransaction: func() CommandProcessor { return &TransactionRequest{} },
+	TypeProcedure:   func() CommandProcessor { return &ProcedureRequest{} },
+}
+
 func RequestByMethod(method string) Request {
 	var processor RequestProcessor
 	switch method {
@@ -56,18 +108,39 @@ func (r *Request) readRequestBody(requestBody io.ReadCloser) (responseError erro
 	return
 }
 
-func (r *Request) getResponse(s storage.Storage) ([]byte, int) {
+// expectETag set version from If-Match header to objects request
+func (r *Request) expectETag(etag string) error {
+	version, err := parseETag(etag)
+	if err != nil {
+		return err
+	}
+
+	if conditional, ok := r.RequestProcessor.(ConditionalRequest); ok && r.Type == TypeObject {
+		return conditional.ExpectVersion(version)
+	}
+	return nil
+}
+
+func (r *Request) getResponse(s storage.Storage) DataCode {
+	if r.command != nil {
+		return r.command.ProcessCommand(s)
+	}
+
 	var response DataCode
 	switch r.Type {
 	case TypeCollection:
 		response = r.ProcessCollection(s)
 	case TypeObject:
 		response = Responses(r.ProcessObjects(s))
+	case TypeStats:
+		response = statsResponse(s)
+	case TypeSettings:
+		response = settingsResponse(r.Type, r.RequestProcessor, s)
 	default:
 		errMsg := errors.ErrMsgUnknownType(r.Type)
 		response = ResponseByError(errMsg)
 	}
-	return response.DataAndCode()
+	return response
 }
 
 func (r *Request) UnmarshalJSON(data []byte) error {
@@ -77,8 +150,23 @@ func (r *Request) UnmarshalJSON(data []byte) error {
 		return err
 	}
 	r.Type = decoder.Type
+	if newCommand, ok := commandsByType[r.Type]; ok {
+		r.command = newCommand()
+		return json.Unmarshal(data, r.command)
+	}
+
 	if err := json.Unmarshal(data, r.RequestProcessor); err != nil {
 		return err
 	}
 	return nil
 }
+
+// settingsResponse - only GET and POST, PUT requests have settings
+func settingsResponse(type_ string, processor RequestProcessor, s storage.Storage) Response {
+	settingsProcessor, ok := processor.(SettingsProcessor)
+	if !ok {
+		errMsg := errors.ErrMsgUnknownType(type_)
+		return ResponseByError(errMsg)
+	}
+	return settingsProcessor.ProcessSettings(s)
+}
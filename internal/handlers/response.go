@@ -5,6 +5,8 @@ import (
 	"net/http"
 
 	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
 )
 
 type (
@@ -12,11 +14,53 @@ type (
 		DataAndCode() (data []byte, code int)
 	}
 
+	// Streamer - response written to client part by part w/o building whole body
+	Streamer interface {
+		Stream(w http.ResponseWriter)
+	}
+
+	// Versioned - response w version of single object
+	Versioned interface {
+		ObjectVersion() uint64
+	}
+
 	// Response - single response
 	Response struct {
 		Data    []byte       `json:"data"`
 		Success bool         `json:"success"`
 		Error   errors.Error `json:"error"`
+		// version of object for objects requests
+		Version uint64 `json:"version,omitempty"`
+		// whether write of object was applied, only for set objects requests
+		Applied *bool `json:"applied,omitempty"`
+		// previous data of object for set objects requests w get option
+		Previous []byte `json:"previous,omitempty"`
+		// values of data type commands
+		Values [][]byte `json:"values,omitempty"`
+		// fields w values of hash commands
+		Fields map[string][]byte `json:"fields,omitempty"`
+		// members of set and sorted set commands
+		Members []string `json:"members,omitempty"`
+		// scores of members of sorted set commands
+		Scores []float64 `json:"scores,omitempty"`
+		// locations of members of geo commands, null for missing member
+		Positions []*object.GeoPoint `json:"positions,omitempty"`
+		// distances of members to center of area for geosearch command
+		Distances []float64 `json:"distances,omitempty"`
+		// entries of stream commands
+		Entries []object.StreamEntry `json:"entries,omitempty"`
+		// pending entries of consumer group for xpending command
+		Pending []PendingEntry `json:"pending,omitempty"`
+		// responses of operations for transaction requests
+		Results []Response `json:"results,omitempty"`
+		// registered procedures by name for procedures admin requests
+		Procedures map[string]storage.Procedure `json:"procedures,omitempty"`
+		// page of keys for scan requests
+		Keys []string `json:"keys,omitempty"`
+		// objects of collection for GET collection requests
+		Objects []ExportedObject `json:"objects,omitempty"`
+		// cursor of the next page for scan and GET collection requests
+		Cursor string `json:"cursor,omitempty"`
 	}
 
 	// Responses - slice of responses
@@ -50,3 +94,15 @@ func (r Responses) DataAndCode() ([]byte, int) {
 
 	return responseData, http.StatusOK
 }
+
+func (r Response) ObjectVersion() uint64 {
+	return r.Version
+}
+
+// ObjectVersion returns version only for response of single object
+func (r Responses) ObjectVersion() uint64 {
+	if len(r) != 1 {
+		return 0
+	}
+	return r[0].Version
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage"
+)
+
+type (
+	// ScanRequest - page of collection keys, response has keys and cursor of the next page
+	ScanRequest struct {
+		Collection string `json:"collection"`
+		// empty cursor starts scan
+		Cursor string `json:"cursor"`
+		// count hint of keys per page
+		Count int `json:"count"`
+		// glob pattern of keys
+		Match string `json:"match"`
+	}
+)
+
+func (r ScanRequest) ProcessCommand(s storage.Storage) Response {
+	keys, next, err := storage.ScanCollection(s, r.Collection, r.Cursor, r.Count, r.Match)
+	if err != nil {
+		errMsg := errors.ErrMsgByError(err, errors.CodeByError(err, http.StatusBadRequest))
+		return ResponseByError(errMsg)
+	}
+
+	return Response{
+		Success: true,
+		Keys:    keys,
+		Cursor:  next,
+	}
+}
@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"encoding/json"
 	"net/http"
 
+	"github.com/mustthink/go-storage-like-redis/config"
 	"github.com/mustthink/go-storage-like-redis/internal/errors"
 	"github.com/mustthink/go-storage-like-redis/internal/storage"
 	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
@@ -12,13 +14,32 @@ type (
 	// PostRequest - POST, PUT collection/objects request, set objects to storage
 	PostRequest struct {
 		Collection         string                            `json:"collection"`
+		Settings           CollectionSettings                `json:"settings"`
 		Objects            map[string]object.RequestSettings `json:"objects"`
 		ObjectsWithoutKeys []object.RequestSettings          `json:"objects_without_keys"`
 	}
+
+	// CollectionSettings - settings of new collection or update of collection settings,
+	// update distinguishes missing fields which keep settings from zero fields which reset them
+	CollectionSettings struct {
+		config.CollectionConfig
+		Update config.CollectionUpdate `json:"-"`
+	}
 )
 
-func (r PostRequest) ProcessCollection(s storage.Storage) Response {
-	return postCollectionResponse(r.Collection, s)
+func (s *CollectionSettings) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &s.CollectionConfig); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &s.Update)
+}
+
+func (r PostRequest) ProcessCollection(s storage.Storage) DataCode {
+	return postCollectionResponse(r.Collection, r.Settings.CollectionConfig, s)
+}
+
+func (r PostRequest) ProcessSettings(s storage.Storage) Response {
+	return postSettingsResponse(r.Collection, r.Settings.Update, s)
 }
 
 func (r PostRequest) ProcessObjects(s storage.Storage) []Response {
@@ -35,8 +56,20 @@ func (r PostRequest) ProcessObjects(s storage.Storage) []Response {
 	return responses
 }
 
-func postCollectionResponse(name string, s storage.Storage) Response {
-	err := s.NewCollection(name)
+func postCollectionResponse(name string, settings config.CollectionConfig, s storage.Storage) Response {
+	err := s.NewCollection(name, settings)
+	if err != nil {
+		errMsg := errors.ErrMsgByError(err, http.StatusBadRequest)
+		return ResponseByError(errMsg)
+	}
+
+	return Response{
+		Success: true,
+	}
+}
+
+func postSettingsResponse(name string, update config.CollectionUpdate, s storage.Storage) Response {
+	err := s.ConfigureCollection(name, update)
 	if err != nil {
 		errMsg := errors.ErrMsgByError(err, http.StatusBadRequest)
 		return ResponseByError(errMsg)
@@ -58,14 +91,37 @@ func postObjectResponse(collectionName, key string, objSettings object.RequestSe
 		key = newKey
 	}
 
-	err := storage.SetObject(s, collectionName, key, objSettings)
+	result, err := storage.SetObject(s, collectionName, key, objSettings)
 	if err != nil {
-		errMsg := errors.ErrMsgByError(err, http.StatusBadRequest)
+		errMsg := errors.ErrMsgByError(err, errors.CodeByError(err, http.StatusBadRequest))
 		return ResponseByError(errMsg)
 	}
 
-	return Response{
+	response := Response{
 		Success: true,
 		Data:    []byte(key),
+		Applied: &result.Applied,
+	}
+	if result.Applied {
+		response.Version = result.Object.Version()
+	}
+	if objSettings.Get && result.Previous != nil {
+		response.Previous = result.Previous.Binary()
+	}
+	return response
+}
+
+// ExpectVersion set expected version for the only object if it's w/o version
+func (r *PostRequest) ExpectVersion(version uint64) error {
+	if len(r.Objects) > 1 {
+		return errors.ErrIfMatchManyObjects
+	}
+
+	for key, objSettings := range r.Objects {
+		if objSettings.Version == 0 {
+			objSettings.Version = version
+			r.Objects[key] = objSettings
+		}
 	}
+	return nil
 }
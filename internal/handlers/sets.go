@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+// set commands
+const (
+	CommandSAdd      = "sadd"
+	CommandSRem      = "srem"
+	CommandSIsMember = "sismember"
+	CommandSMembers  = "smembers"
+	CommandSCard     = "scard"
+	CommandSPop      = "spop"
+	CommandSInter    = "sinter"
+	CommandSUnion    = "sunion"
+	CommandSDiff     = "sdiff"
+)
+
+type (
+	// SetRequest - atomic operation on set object, response data is count or "true"/"false",
+	// response members are members of set or result of sets algebra
+	SetRequest struct {
+		Collection string `json:"collection"`
+		Key        string `json:"key"`
+		Command    string `json:"command"`
+
+		// members for sadd, srem
+		Members []string `json:"members"`
+		// member for sismember
+		Member string `json:"member"`
+		// count of members for spop, default 1
+		Count int `json:"count"`
+		// keys of sets for sinter, sunion, sdiff, empty collection of key is collection of request
+		Keys []storage.ObjectRef `json:"keys"`
+
+		// expiration settings of new set
+		Expiration object.RequestSettings `json:"expiration"`
+	}
+)
+
+func (r SetRequest) ProcessCommand(s storage.Storage) Response {
+	var (
+		response = Response{Success: true}
+		count    int
+		err      error
+	)
+	switch r.Command {
+	case CommandSAdd:
+		count, err = storage.SetAdd(s, r.Collection, r.Key, r.Members, r.Expiration)
+		response.Data = strconv.AppendInt(nil, int64(count), 10)
+	case CommandSRem:
+		count, err = storage.SetRemove(s, r.Collection, r.Key, r.Members)
+		response.Data = strconv.AppendInt(nil, int64(count), 10)
+	case CommandSIsMember:
+		var isMember bool
+		isMember, err = storage.SetIsMember(s, r.Collection, r.Key, r.Member)
+		response.Data = []byte(strconv.FormatBool(isMember))
+	case CommandSMembers:
+		response.Members, err = storage.SetMembers(s, r.Collection, r.Key)
+	case CommandSCard:
+		count, err = storage.SetCard(s, r.Collection, r.Key)
+		response.Data = strconv.AppendInt(nil, int64(count), 10)
+	case CommandSPop:
+		count := r.Count
+		if count == 0 {
+			count = 1
+		}
+		response.Members, err = storage.SetPop(s, r.Collection, r.Key, count)
+	case CommandSInter:
+		response.Members, err = storage.SetInter(s, r.refs())
+	case CommandSUnion:
+		response.Members, err = storage.SetUnion(s, r.refs())
+	case CommandSDiff:
+		response.Members, err = storage.SetDiff(s, r.refs())
+	default:
+		err = errors.ErrUnknownCommand(r.Command)
+	}
+
+	if err != nil {
+		errMsg := errors.ErrMsgByError(err, errors.CodeByError(err, http.StatusBadRequest))
+		return ResponseByError(errMsg)
+	}
+	return response
+}
+
+func (r SetRequest) refs() []storage.ObjectRef {
+	return requestRefs(r.Collection, r.Keys)
+}
+
+// requestRefs returns keys w collection of request for keys w/o collection
+func requestRefs(collection string, keys []storage.ObjectRef) []storage.ObjectRef {
+	refs := make([]storage.ObjectRef, len(keys))
+	for i, ref := range keys {
+		if ref.Collection == "" {
+			ref.Collection = collection
+		}
+		refs[i] = ref
+	}
+	return refs
+}
@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage"
+)
+
+// statsResponse - storage counters for any request method
+func statsResponse(s storage.Storage) Response {
+	data, err := json.Marshal(s.Stats())
+	if err != nil {
+		errMsg := errors.ErrMsgByError(err, http.StatusInternalServerError)
+		return ResponseByError(errMsg)
+	}
+
+	return Response{
+		Data:    data,
+		Success: true,
+	}
+}
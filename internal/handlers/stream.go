@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+// stream commands
+const (
+	CommandXAdd       = "xadd"
+	CommandXRange     = "xrange"
+	CommandXLen       = "xlen"
+	CommandXTrim      = "xtrim"
+	CommandXGroup     = "xgroup"
+	CommandXReadGroup = "xreadgroup"
+	CommandXAck       = "xack"
+	CommandXPending   = "xpending"
+	CommandXClaim     = "xclaim"
+)
+
+type (
+	// StreamRequest - atomic operation on stream object, response data is id of added entry or count,
+	// response entries are entries of range or delivered entries, response pending are pending entries of group
+	StreamRequest struct {
+		Collection string `json:"collection"`
+		Key        string `json:"key"`
+		Command    string `json:"command"`
+
+		// id of new entry for xadd, "*" or empty - generated, id after which group delivers entries for xgroup,
+		// "$" or empty - the last entry, or id for xreadgroup, ">" or empty - new entries, other - pending entries after id
+		ID string `json:"id"`
+		// fields of new entry for xadd
+		Fields map[string][]byte `json:"fields"`
+		// max count of entries for xadd, xtrim
+		MaxLen int `json:"max_len"`
+		// max age of entries in seconds for xtrim
+		MaxAge time.Duration `json:"max_age"`
+		// inclusive ids for xrange, "-" and "+" are the minimal and the maximal ids
+		Start string `json:"start"`
+		End   string `json:"end"`
+		// max count of entries for xrange, xreadgroup, xpending, 0 - all
+		Count int `json:"count"`
+		// consumer group for xgroup, xreadgroup, xack, xpending, xclaim
+		Group string `json:"group"`
+		// consumer for xreadgroup, xclaim or optional consumer for xpending
+		Consumer string `json:"consumer"`
+		// ids of entries for xack, xclaim
+		IDs []string `json:"ids"`
+		// min idle time of pending entries for xclaim
+		MinIdleInMs int64 `json:"min_idle_in_ms"`
+
+		// expiration settings of new stream
+		Expiration object.RequestSettings `json:"expiration"`
+	}
+
+	// PendingEntry - entry delivered to consumer of group but not acknowledged
+	PendingEntry struct {
+		ID         string `json:"id"`
+		Consumer   string `json:"consumer"`
+		IdleInMs   int64  `json:"idle_in_ms"`
+		Deliveries int    `json:"deliveries"`
+	}
+)
+
+func (r StreamRequest) ProcessCommand(s storage.Storage) Response {
+	var (
+		response = Response{Success: true}
+		count    int
+		err      error
+	)
+	switch r.Command {
+	case CommandXAdd:
+		var id string
+		id, err = storage.StreamAdd(s, r.Collection, r.Key, r.ID, r.Fields, r.MaxLen, r.Expiration)
+		response.Data = []byte(id)
+	case CommandXRange:
+		response.Entries, err = storage.StreamRange(s, r.Collection, r.Key, r.Start, r.End, r.Count)
+	case CommandXLen:
+		count, err = storage.StreamLen(s, r.Collection, r.Key)
+		response.Data = strconv.AppendInt(nil, int64(count), 10)
+	case CommandXTrim:
+		count, err = storage.StreamTrim(s, r.Collection, r.Key, r.MaxLen, r.MaxAge*time.Second)
+		response.Data = strconv.AppendInt(nil, int64(count), 10)
+	case CommandXGroup:
+		err = storage.StreamGroupCreate(s, r.Collection, r.Key, r.Group, r.ID, r.Expiration)
+	case CommandXReadGroup:
+		response.Entries, err = storage.StreamReadGroup(s, r.Collection, r.Key, r.Group, r.Consumer, r.ID, r.Count)
+	case CommandXAck:
+		count, err = storage.StreamAck(s, r.Collection, r.Key, r.Group, r.IDs)
+		response.Data = strconv.AppendInt(nil, int64(count), 10)
+	case CommandXPending:
+		var pending []object.PendingEntry
+		pending, err = storage.StreamPending(s, r.Collection, r.Key, r.Group, r.Consumer, r.Count)
+		response.Pending = pendingEntries(pending)
+	case CommandXClaim:
+		minIdle := time.Duration(r.MinIdleInMs) * time.Millisecond
+		response.Entries, err = storage.StreamClaim(s, r.Collection, r.Key, r.Group, r.Consumer, minIdle, r.IDs)
+	default:
+		err = errors.ErrUnknownCommand(r.Command)
+	}
+
+	if err != nil {
+		errMsg := errors.ErrMsgByError(err, errors.CodeByError(err, http.StatusBadRequest))
+		return ResponseByError(errMsg)
+	}
+	return response
+}
+
+func pendingEntries(pending []object.PendingEntry) []PendingEntry {
+	now := time.Now()
+	entries := make([]PendingEntry, len(pending))
+	for i, entry := range pending {
+		entries[i] = PendingEntry{
+			ID:         entry.ID,
+			Consumer:   entry.Consumer,
+			IdleInMs:   now.Sub(entry.Delivered).Milliseconds(),
+			Deliveries: entry.Deliveries,
+		}
+	}
+	return entries
+}
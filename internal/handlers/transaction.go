@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage"
+)
+
+type (
+	// TransactionRequest - operations executed atomically and in isolation, either all of them
+	// are applied or none, response results are responses of operations in order of request,
+	// transaction is aborted if any watched object was changed since its version was read
+	TransactionRequest struct {
+		Watch      []storage.WatchedObject `json:"watch"`
+		Operations []storage.Operation     `json:"operations"`
+	}
+)
+
+func (r TransactionRequest) ProcessCommand(s storage.Storage) Response {
+	results, err := storage.ExecTransaction(s, r.Watch, r.Operations)
+	if err != nil {
+		errMsg := errors.ErrMsgByError(err, errors.CodeByError(err, http.StatusBadRequest))
+		return ResponseByError(errMsg)
+	}
+
+	response := Response{Success: true, Results: make([]Response, len(results))}
+	for i, result := range results {
+		response.Results[i] = operationResponse(r.Operations[i].Command, result)
+	}
+	return response
+}
+
+// operationResponse - data of object after operation, only the last write of object has version
+func operationResponse(command string, result storage.SetResult) Response {
+	response := Response{Success: true}
+	if command != storage.OperationGet {
+		applied := result.Applied
+		response.Applied = &applied
+	}
+	if result.Object != nil {
+		response.Data = result.Object.Binary()
+		response.Version = result.Object.Version()
+	}
+	if result.Previous != nil {
+		response.Previous = result.Previous.Binary()
+	}
+	return response
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+// ttl commands
+const (
+	CommandTTL      = "ttl"
+	CommandExpire   = "expire"
+	CommandExpireAt = "expireat"
+	CommandPersist  = "persist"
+	CommandTouch    = "touch"
+)
+
+type (
+	// TTLRequest - inspection and change of object expiration,
+	// response data is remaining TTL in seconds, -1 for timeless object
+	TTLRequest struct {
+		Collection string `json:"collection"`
+		Key        string `json:"key"`
+		Command    string `json:"command"`
+
+		// timeout in seconds for expire, not positive timeout deletes object
+		Timeout time.Duration `json:"timeout"`
+		// deadline for expireat, passed deadline deletes object
+		Deadline time.Time `json:"deadline"`
+	}
+)
+
+func (r TTLRequest) ProcessCommand(s storage.Storage) Response {
+	if r.Command == CommandTTL {
+		ttl, err := storage.ObjectTTL(s, r.Collection, r.Key)
+		if err != nil {
+			errMsg := errors.ErrMsgByError(err, errors.CodeByError(err, http.StatusBadRequest))
+			return ResponseByError(errMsg)
+		}
+
+		return Response{
+			Data:    strconv.AppendInt(nil, ttlSeconds(ttl), 10),
+			Success: true,
+		}
+	}
+
+	var (
+		expiration object.RequestSettings
+		err        error
+	)
+	switch r.Command {
+	case CommandExpire:
+		if r.Timeout == 0 {
+			err = errors.ErrEmptyField("timeout")
+		}
+		expiration.Timeout = r.Timeout
+	case CommandExpireAt:
+		if r.Deadline.IsZero() {
+			err = errors.ErrEmptyField("deadline")
+		}
+		expiration.Deadline = r.Deadline
+	case CommandPersist:
+		expiration.Timeless = true
+	case CommandTouch:
+		// empty expiration is default TTL of collection
+	default:
+		err = errors.ErrUnknownCommand(r.Command)
+	}
+
+	var obj object.Object
+	if err == nil {
+		obj, err = storage.ExpireObject(s, r.Collection, r.Key, expiration)
+	}
+	if err != nil {
+		errMsg := errors.ErrMsgByError(err, errors.CodeByError(err, http.StatusBadRequest))
+		return ResponseByError(errMsg)
+	}
+
+	// object is deleted by passed expiration
+	if obj == nil {
+		return Response{
+			Data:    []byte("0"),
+			Success: true,
+		}
+	}
+
+	return Response{
+		Data:    strconv.AppendInt(nil, ttlSeconds(obj.TTL()), 10),
+		Success: true,
+		Version: obj.Version(),
+	}
+}
+
+// ttlSeconds returns ttl in seconds rounded to nearest, -1 for timeless object
+func ttlSeconds(ttl time.Duration) int64 {
+	if ttl < 0 {
+		return -1
+	}
+	return int64(ttl.Round(time.Second) / time.Second)
+}
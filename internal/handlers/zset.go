@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+// sorted set commands
+const (
+	CommandZAdd          = "zadd"
+	CommandZIncrBy       = "zincrby"
+	CommandZScore        = "zscore"
+	CommandZRank         = "zrank"
+	CommandZRange        = "zrange"
+	CommandZRangeByScore = "zrangebyscore"
+	CommandZRem          = "zrem"
+	CommandZPopMin       = "zpopmin"
+	CommandZPopMax       = "zpopmax"
+)
+
+type (
+	// ZSetRequest - atomic operation on sorted set object, response data is count, score or rank,
+	// response members w scores are members of range or popped members
+	ZSetRequest struct {
+		Collection string `json:"collection"`
+		Key        string `json:"key"`
+		Command    string `json:"command"`
+
+		// scores of members for zadd
+		Scores map[string]float64 `json:"scores"`
+		// conditions of zadd
+		object.ZAddOptions
+		// member for zincrby, zscore, zrank
+		Member string `json:"member"`
+		// members for zrem
+		Members []string `json:"members"`
+		// increment for zincrby
+		Increment float64 `json:"increment"`
+		// inclusive ranks for zrange
+		Start int `json:"start"`
+		Stop  int `json:"stop"`
+		// bounds of scores for zrangebyscore: number, "-inf", "+inf", "(" prefix for exclusive bound
+		Min string `json:"min"`
+		Max string `json:"max"`
+		// max count of members for zrangebyscore, 0 - all, or count for zpopmin, zpopmax, default 1
+		Count int `json:"count"`
+
+		// expiration settings of new sorted set
+		Expiration object.RequestSettings `json:"expiration"`
+	}
+)
+
+func (r ZSetRequest) ProcessCommand(s storage.Storage) Response {
+	var (
+		response = Response{Success: true}
+		members  []object.ScoredMember
+		err      error
+	)
+	switch r.Command {
+	case CommandZAdd:
+		var added int
+		added, err = storage.ZSetAdd(s, r.Collection, r.Key, r.scoredMembers(), r.ZAddOptions, r.Expiration)
+		response.Data = strconv.AppendInt(nil, int64(added), 10)
+	case CommandZIncrBy:
+		var score float64
+		score, err = storage.ZSetIncrement(s, r.Collection, r.Key, r.Member, r.Increment, r.Expiration)
+		response.Data = strconv.AppendFloat(nil, score, 'f', -1, 64)
+	case CommandZScore:
+		score, ok, scoreErr := storage.ZSetScore(s, r.Collection, r.Key, r.Member)
+		if ok {
+			response.Data = strconv.AppendFloat(nil, score, 'f', -1, 64)
+		}
+		err = scoreErr
+	case CommandZRank:
+		rank, ok, rankErr := storage.ZSetRank(s, r.Collection, r.Key, r.Member)
+		if ok {
+			response.Data = strconv.AppendInt(nil, int64(rank), 10)
+		}
+		err = rankErr
+	case CommandZRange:
+		members, err = storage.ZSetRange(s, r.Collection, r.Key, r.Start, r.Stop)
+	case CommandZRangeByScore:
+		members, err = r.rangeByScore(s)
+	case CommandZRem:
+		var removed int
+		removed, err = storage.ZSetRemove(s, r.Collection, r.Key, r.Members)
+		response.Data = strconv.AppendInt(nil, int64(removed), 10)
+	case CommandZPopMin, CommandZPopMax:
+		count := r.Count
+		if count == 0 {
+			count = 1
+		}
+		members, err = storage.ZSetPop(s, r.Collection, r.Key, r.Command == CommandZPopMin, count)
+	default:
+		err = errors.ErrUnknownCommand(r.Command)
+	}
+
+	if err != nil {
+		errMsg := errors.ErrMsgByError(err, errors.CodeByError(err, http.StatusBadRequest))
+		return ResponseByError(errMsg)
+	}
+
+	for _, member := range members {
+		response.Members = append(response.Members, member.Member)
+		response.Scores = append(response.Scores, member.Score)
+	}
+	return response
+}
+
+// scoredMembers returns members w scores of zadd request
+func (r ZSetRequest) scoredMembers() []object.ScoredMember {
+	members := make([]object.ScoredMember, 0, len(r.Scores))
+	for member, score := range r.Scores {
+		members = append(members, object.ScoredMember{Member: member, Score: score})
+	}
+	return members
+}
+
+func (r ZSetRequest) rangeByScore(s storage.Storage) ([]object.ScoredMember, error) {
+	min, err := object.ParseScoreBound(r.Min)
+	if err != nil {
+		return nil, err
+	}
+	max, err := object.ParseScoreBound(r.Max)
+	if err != nil {
+		return nil, err
+	}
+	return storage.ZSetRangeByScore(s, r.Collection, r.Key, min, max, r.Count)
+}
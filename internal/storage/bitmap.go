@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+// SetBit atomically sets bit at offset of binary object and returns previous bit,
+// missing object is created w expiration settings, object is extended by zero bytes to fit offset,
+// only changed page of object is copied and skipped bytes aren't allocated,
+// so object over memory limit is rejected by collection before eviction
+func SetBit(s Storage, collectionName, objectKey string, offset uint64, bit bool, expiration object.RequestSettings) (bool, error) {
+	var previous bool
+	_, err := updateBits(s, collectionName, objectKey, expiration, func(bits object.Bits) (object.Bits, error) {
+		previous = bits.GetBit(offset)
+		// write of already set bit is skipped
+		if previous == bit && offset < uint64(bits.Len())*8 {
+			return bits, SkipUpdate
+		}
+
+		updated, _, err := bits.SetBit(offset, bit)
+		return updated, err
+	})
+	return previous, err
+}
+
+// GetBit returns bit at offset of binary object, bit of missing object is 0
+func GetBit(s Storage, collectionName, objectKey string, offset uint64) (bool, error) {
+	bits, err := viewBits(s, collectionName, objectKey)
+	return bits.GetBit(offset), err
+}
+
+// BitCount returns count of set bits of binary object in range from start to end inclusive,
+// negative index is counted from the end
+func BitCount(s Storage, collectionName, objectKey string, start, end int, unit object.BitUnit) (int, error) {
+	if err := unit.Validate(); err != nil {
+		return 0, err
+	}
+
+	bits, err := viewBits(s, collectionName, objectKey)
+	if err != nil {
+		return 0, err
+	}
+	return bits.BitCount(start, end, unit), nil
+}
+
+// BitPos returns position of the first bit equal to bit in range from start to end inclusive or -1,
+// nil end is the end of object, clear bit is searched after the end of object if end isn't set
+func BitPos(s Storage, collectionName, objectKey string, bit bool, start int, end *int, unit object.BitUnit) (int, error) {
+	if err := unit.Validate(); err != nil {
+		return 0, err
+	}
+
+	bits, err := viewBits(s, collectionName, objectKey)
+	if err != nil {
+		return 0, err
+	}
+
+	if end != nil {
+		return bits.BitPos(bit, start, *end, unit), nil
+	}
+
+	position := bits.BitPos(bit, start, -1, unit)
+	if position == -1 && !bit {
+		return bits.Len() * 8, nil
+	}
+	return position, nil
+}
+
+// BitOp atomically stores result of operation on binary objects of sources to target and returns length of result,
+// missing source is empty, target of any type is replaced by object w expiration settings or deleted if result is empty
+func BitOp(s Storage, operation object.BitOperation, target ObjectRef, sources []ObjectRef, expiration object.RequestSettings) (int, error) {
+	if len(sources) == 0 {
+		return 0, errors.ErrEmptyField("keys")
+	}
+
+	collection, err := s.GetCollection(target.Collection)
+	if err != nil {
+		return 0, err
+	}
+
+	// object is created before update, so collection settings aren't read under collection lock
+	created := newObject(s, collection, expiration)
+	var length int
+	_, err = updateWithObjects(s, target, sources, func(_ object.Object, objects []object.Object) (object.Object, error) {
+		sources := make([]object.Bits, len(objects))
+		for i, obj := range objects {
+			if obj == nil {
+				continue
+			}
+			if obj.Type() != object.TypeString {
+				return nil, errors.ErrWrongType
+			}
+			sources[i] = obj.Bits()
+		}
+
+		result, err := object.BitOp(operation, sources...)
+		if err != nil {
+			return nil, err
+		}
+
+		length = result.Len()
+		if length == 0 {
+			return nil, nil
+		}
+		return created.WithBits(result), nil
+	})
+	return length, err
+}
@@ -0,0 +1,145 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mustthink/go-storage-like-redis/config"
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+func TestBitmap(t *testing.T) {
+	testStorage := New(testConfig)
+
+	for _, offset := range []uint64{3, 10, 11} {
+		previous, err := SetBit(testStorage, testCollection, "users", offset, true, object.RequestSettings{})
+		require.Nil(t, err)
+		assert.False(t, previous)
+	}
+
+	// write of the same bit isn't applied
+	version := objectVersion(t, testStorage, "users")
+	previous, err := SetBit(testStorage, testCollection, "users", 10, true, object.RequestSettings{})
+	require.Nil(t, err)
+	assert.True(t, previous)
+	assert.Equal(t, version, objectVersion(t, testStorage, "users"))
+
+	obj, err := GetObject(testStorage, testCollection, "users")
+	require.Nil(t, err)
+	assert.Equal(t, []byte{0x10, 0x30}, obj.Binary())
+
+	bit, err := GetBit(testStorage, testCollection, "users", 11)
+	require.Nil(t, err)
+	assert.True(t, bit)
+
+	count, err := BitCount(testStorage, testCollection, "users", 0, -1, "")
+	require.Nil(t, err)
+	assert.Equal(t, 3, count)
+
+	position, err := BitPos(testStorage, testCollection, "users", true, 1, nil, object.BitUnitByte)
+	require.Nil(t, err)
+	assert.Equal(t, 10, position)
+
+	// clear bit is found after the end of object w/o end of range
+	require.Nil(t, setObject(testStorage, testCollection, "full", object.RequestSettings{Data: []byte{0xFF}}))
+	position, err = BitPos(testStorage, testCollection, "full", false, 0, nil, "")
+	require.Nil(t, err)
+	assert.Equal(t, 8, position)
+	end := 0
+	position, err = BitPos(testStorage, testCollection, "full", false, 0, &end, "")
+	require.Nil(t, err)
+	assert.Equal(t, -1, position)
+
+	// missing object is empty
+	count, err = BitCount(testStorage, testCollection, "unknown", 0, -1, "")
+	require.Nil(t, err)
+	assert.Equal(t, 0, count)
+
+	_, err = BitCount(testStorage, testCollection, "users", 0, -1, "word")
+	assert.Equal(t, errors.ErrUnknownValue("unit", "word"), err)
+
+	_, err = ListPush(testStorage, testCollection, "list", true, [][]byte{[]byte("a")}, object.RequestSettings{})
+	require.Nil(t, err)
+	_, err = SetBit(testStorage, testCollection, "list", 0, true, object.RequestSettings{})
+	assert.Equal(t, errors.ErrWrongType, err)
+	_, err = GetBit(testStorage, testCollection, "list", 0)
+	assert.Equal(t, errors.ErrWrongType, err)
+}
+
+func TestSetBit_MaxMemory(t *testing.T) {
+	storageConfig := testConfig
+	storageConfig.MaxMemory = 1 << 20
+	testStorage := New(storageConfig)
+
+	_, err := SetBit(testStorage, testCollection, "users", 0, true, object.RequestSettings{})
+	require.Nil(t, err)
+
+	// object extended over memory limit is rejected, skipped bytes aren't allocated
+	_, err = SetBit(testStorage, testCollection, "users", object.MaxBitOffset, true, object.RequestSettings{})
+	assert.ErrorIs(t, err, errors.ErrOutOfMemory)
+
+	obj, err := GetObject(testStorage, testCollection, "users")
+	require.Nil(t, err)
+	assert.Equal(t, []byte{0x80}, obj.Binary())
+}
+
+func TestBitmap_Sparse(t *testing.T) {
+	testStorage := New(testConfig)
+	_, err := SetBit(testStorage, testCollection, "sparse", object.MaxBitOffset, true, object.RequestSettings{})
+	require.Nil(t, err)
+
+	// bit operations read only existing pages of object
+	count, err := BitCount(testStorage, testCollection, "sparse", 0, -1, "")
+	require.Nil(t, err)
+	assert.Equal(t, 1, count)
+	position, err := BitPos(testStorage, testCollection, "sparse", true, 0, nil, "")
+	require.Nil(t, err)
+	assert.Equal(t, object.MaxBitOffset, position)
+
+	sources := []ObjectRef{{Key: "sparse"}, {Key: "sparse"}}
+	length, err := BitOp(testStorage, object.BitOpXor, ObjectRef{Key: "result"}, sources, object.RequestSettings{})
+	require.Nil(t, err)
+	assert.Equal(t, object.MaxBitOffset/8+1, length)
+	count, err = BitCount(testStorage, testCollection, "result", 0, -1, "")
+	require.Nil(t, err)
+	assert.Equal(t, 0, count)
+}
+
+func TestBitOp(t *testing.T) {
+	storageConfig := testConfig
+	storageConfig.MaxCollectionsCount = 2
+	testStorage := New(storageConfig)
+	require.Nil(t, testStorage.NewCollection("other", config.CollectionConfig{ShardsCount: 4}))
+
+	require.Nil(t, setObject(testStorage, testCollection, "monday", object.RequestSettings{Data: []byte{0xF0, 0x0F}}))
+	_, err := SetBit(testStorage, "other", "tuesday", 0, true, object.RequestSettings{})
+	require.Nil(t, err)
+
+	sources := []ObjectRef{{Key: "monday"}, {Collection: "other", Key: "tuesday"}}
+	target := ObjectRef{Collection: "other", Key: "both"}
+	length, err := BitOp(testStorage, object.BitOpAnd, target, sources, object.RequestSettings{Timeless: true})
+	require.Nil(t, err)
+	assert.Equal(t, 2, length)
+
+	obj, err := GetObject(testStorage, "other", "both")
+	require.Nil(t, err)
+	assert.Equal(t, []byte{0x80, 0x00}, obj.Binary())
+	assert.True(t, obj.IsTimeless())
+
+	// empty result deletes target
+	length, err = BitOp(testStorage, object.BitOpOr, target, []ObjectRef{{Key: "unknown"}}, object.RequestSettings{})
+	require.Nil(t, err)
+	assert.Equal(t, 0, length)
+	_, err = GetObject(testStorage, "other", "both")
+	assert.Equal(t, errors.ErrNoObject("both"), err)
+
+	_, err = ListPush(testStorage, testCollection, "list", true, [][]byte{[]byte("a")}, object.RequestSettings{})
+	require.Nil(t, err)
+	_, err = BitOp(testStorage, object.BitOpOr, target, append(sources, ObjectRef{Key: "list"}), object.RequestSettings{})
+	assert.Equal(t, errors.ErrWrongType, err)
+	_, err = BitOp(testStorage, object.BitOpNot, target, sources, object.RequestSettings{})
+	assert.Equal(t, errors.ErrBitOpNotSingleKey, err)
+}
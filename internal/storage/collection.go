@@ -2,8 +2,13 @@ package storage
 
 import (
 	"context"
+	"fmt"
+	"math/rand"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/mustthink/go-storage-like-redis/config"
 	"github.com/mustthink/go-storage-like-redis/internal/errors"
 	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
 )
@@ -11,64 +16,497 @@ import (
 type (
 	Collection interface {
 		Get(key string) (object object.Object, err error)
-		Set(key string, object object.Object)
+		// Peek returns object w/o reading it: sliding expiration isn't extended and object isn't touched for eviction
+		Peek(key string) (object object.Object, err error)
+		Set(key string, object object.Object) error
+		// Update atomically replaces object by result of update func and returns stored object
+		Update(key string, update UpdateFunc) (object object.Object, err error)
 		Delete(key string) error
+		// Scan returns page of objects w keys matching glob pattern after cursor and cursor of the next page
+		Scan(cursor string, count int, match string) (entries []Entry, next string, err error)
 		Refresh(context context.Context)
+		SampleExpired(sampleSize int) (sampled, expired int)
+		Stats() CollectionStats
+		// Drop deletes all objects and releases their memory
+		Drop()
+
+		Settings() config.CollectionConfig
+		// Configure changes settings by non-nil fields of update, new limits are applied on the next writes
+		Configure(update config.CollectionUpdate)
+
+		// shardOf returns shard which holds key
+		shardOf(key string) collection
+		// allShards returns shards which share limits of collection
+		allShards() []collection
 	}
 
 	// collection is simple implementation of Collection
 	collection struct {
+		// unique id of collection shard, it orders locks of several shards
+		id      uint64
 		objects map[string]object.Object
-		mu      *sync.RWMutex
+		// ordered keys of objects for scan
+		index    *object.Keys
+		expiry   *expiryIndex
+		settings *settings
+		counters *counters
+		mu       *sync.RWMutex
+	}
+
+	// settings of collection, guarded by collection lock
+	settings struct {
+		config  config.CollectionConfig
+		evictor evictor
+	}
+
+	// counters of collection, they can be shared by collection shards
+	counters struct {
+		memory    *quota
+		keys      *quota
+		evictions *atomic.Uint64
+		// the last version of objects, every write gets the next one
+		version *atomic.Uint64
 	}
+
+	// UpdateFunc gets current object or nil if it doesn't exist and returns new object,
+	// nil new object deletes current one, error cancels update,
+	// SkipUpdate error leaves current object unchanged and Update returns it w/o error
+	UpdateFunc func(current object.Object) (updated object.Object, err error)
+
+	CollectionOpt func(collection) collection
 )
 
-func NewCollection() Collection {
+// SkipUpdate is returned by update func for leaving current object unchanged
+var SkipUpdate = fmt.Errorf("skip update")
+
+// max count of expired objects deleted per one lock while refreshing
+const refreshBatch = 64
+
+// the last id of collection shards
+var lastCollectionID atomic.Uint64
+
+func NewCollection(opts ...CollectionOpt) Collection {
+	keys := object.NewKeys()
 	collection := collection{
-		objects: make(map[string]object.Object),
-		mu:      &sync.RWMutex{},
+		id:       lastCollectionID.Add(1),
+		objects:  make(map[string]object.Object),
+		index:    &keys,
+		expiry:   newExpiryIndex(),
+		settings: &settings{},
+		counters: newCounters(nil, nil),
+		mu:       &sync.RWMutex{},
 	}
+
+	for _, opt := range opts {
+		collection = opt(collection)
+	}
+
 	return collection
 }
 
+// newCounters creates counters w collection memory as part of storage memory
+// and versions of storage, nil version creates own versions of collection
+func newCounters(storageMemory *quota, version *atomic.Uint64) *counters {
+	if version == nil {
+		version = &atomic.Uint64{}
+	}
+	return &counters{
+		memory:    newQuota(0, storageMemory),
+		keys:      newQuota(0, nil),
+		evictions: &atomic.Uint64{},
+		version:   version,
+	}
+}
+
+// withCounters set counters, they should be set before config
+func withCounters(counters *counters) CollectionOpt {
+	return func(c collection) collection {
+		c.counters = counters
+		return c
+	}
+}
+
+// withConfig set settings and limits of collection
+func withConfig(collectionConfig config.CollectionConfig) CollectionOpt {
+	return func(c collection) collection {
+		c.settings = &settings{}
+		c.configure(collectionConfig)
+		return c
+	}
+}
+
 func (c collection) Get(key string) (object.Object, error) {
 	c.mu.RLock()
 	obj, ok := c.objects[key]
+	evictor := c.settings.evictor
 	c.mu.RUnlock()
 	if !ok {
 		return nil, errors.ErrNoObject(key)
 	}
 
 	if obj.IsExpired() {
-		c.Delete(key)
+		c.deleteExpired(key)
 		return nil, errors.ErrNoObject(key)
 	}
 
+	if obj.IsSliding() {
+		obj = c.slide(key, obj)
+	}
+
+	if evictor != nil {
+		c.touch(key)
+	}
+
+	return obj, nil
+}
+
+func (c collection) Peek(key string) (object.Object, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	obj := c.peek(key)
+	if obj == nil {
+		return nil, errors.ErrNoObject(key)
+	}
 	return obj, nil
 }
 
-func (c collection) Set(key string, object object.Object) {
+func (c collection) Set(key string, object object.Object) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, err := c.set(key, object)
+	return err
+}
+
+func (c collection) Update(key string, update UpdateFunc) (object.Object, error) {
 	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.update(key, update)
+}
+
+// update object w/o lock
+func (c collection) update(key string, update UpdateFunc) (object.Object, error) {
+	current, exists := c.objects[key]
+	if exists && current.IsExpired() {
+		c.delete(key)
+		current, exists = nil, false
+	}
+
+	updated, err := update(current)
+	if err == SkipUpdate {
+		return current, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if updated == nil {
+		if exists {
+			c.delete(key)
+		}
+		return nil, nil
+	}
+	return c.set(key, updated)
+}
+
+// set object w/o lock, returns object w new version
+func (c collection) set(key string, object object.Object) (object.Object, error) {
+	return c.setEvicting(key, object, nil)
+}
+
+// setEvicting sets object w/o lock and calls evicted for every object evicted to fit new object, evicted could be nil
+func (c collection) setEvicting(key string, object object.Object, evicted func(key string, obj object.Object)) (object.Object, error) {
+	if object.IsTimeless() && c.settings.config.NoTimeless {
+		return nil, errors.ErrTimelessForbidden
+	}
+
+	size := objectSize(key, object.Size())
+	if c.counters.memory.exceeds(size) {
+		// nothing is evicted for object which couldn't fit at all
+		return nil, errors.ErrObjectTooLarge(size)
+	}
+
+	old, exists := c.objects[key]
+	delta := size
+	if exists {
+		delta -= objectSize(key, old.Size())
+	}
+
+	// evict objects until new object fits into limits
+	for {
+		err := c.checkLimits(delta, exists)
+		if err == nil {
+			break
+		}
+
+		victim, victimObject, ok := c.evictVictim()
+		if !ok {
+			return nil, err
+		}
+		if evicted != nil {
+			evicted(victim, victimObject)
+		}
+		if victim == key {
+			delta, exists = size, false
+		}
+	}
+
+	object = object.WithVersion(c.counters.version.Add(1))
+	c.store(key, object)
+	return object, nil
+}
+
+// store object w/o lock and limits checks
+func (c collection) store(key string, object object.Object) {
+	old, exists := c.objects[key]
+	delta := objectSize(key, object.Size())
+	if exists {
+		delta -= objectSize(key, old.Size())
+	}
+
 	c.objects[key] = object
-	c.mu.Unlock()
+	c.counters.memory.add(delta)
+	if !exists {
+		c.counters.keys.add(1)
+		*c.index = c.index.Add(key)
+	}
+	if object.IsTimeless() {
+		c.expiry.remove(key)
+	} else {
+		c.expiry.set(key, object.Expires())
+	}
+	if evictor := c.settings.evictor; evictor != nil {
+		object.Touch()
+		evictor.touch(key, object)
+	}
+}
+
+// restore puts back object w its version w/o lock and limits checks, nil object is deleted
+func (c collection) restore(key string, object object.Object) {
+	c.delete(key)
+	if object != nil {
+		c.store(key, object)
+	}
+}
+
+// checkLimits checks that object w delta size fits into memory and keys limits
+func (c collection) checkLimits(delta int64, exists bool) error {
+	switch {
+	case !c.counters.memory.fits(delta):
+		return errors.ErrOutOfMemory
+	case !exists && !c.counters.keys.fits(1):
+		return errors.ErrMaxKeys
+	default:
+		return nil
+	}
 }
 
 func (c collection) Delete(key string) error {
-	_, ok := c.objects[key]
-	if !ok {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.objects[key]; !ok {
 		return errors.ErrNoObject(key)
 	}
 
-	c.mu.Lock()
-	delete(c.objects, key)
-	c.mu.Unlock()
+	c.delete(key)
 	return nil
 }
 
-func (c collection) Refresh(_ context.Context) {
-	for key, obj := range c.objects {
-		if obj.IsExpired() {
-			c.Delete(key)
+// Refresh deletes only expired objects by expiry index,
+// lock is released between batches so writers aren't stalled
+func (c collection) Refresh(ctx context.Context) {
+	for ctx.Err() == nil {
+		if !c.refreshBatch(time.Now()) {
+			return
 		}
 	}
 }
+
+// refreshBatch returns true if there may be more expired objects
+func (c collection) refreshBatch(now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i := 0; i < refreshBatch; i++ {
+		key, ok := c.expiry.due(now)
+		if !ok {
+			return false
+		}
+		c.delete(key)
+	}
+	return true
+}
+
+// SampleExpired checks random objects w expiration time and deletes expired ones
+func (c collection) SampleExpired(sampleSize int) (sampled, expired int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for ; sampled < sampleSize && c.expiry.len() > 0; sampled++ {
+		item := c.expiry.items[rand.Intn(c.expiry.len())]
+		if item.expires.Before(now) {
+			c.delete(item.key)
+			expired++
+		}
+	}
+	return sampled, expired
+}
+
+// deleteExpired deletes object only if it's still expired,
+// it could be overwritten since it was read
+func (c collection) deleteExpired(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if obj, ok := c.objects[key]; ok && obj.IsExpired() {
+		c.delete(key)
+	}
+}
+
+func (c collection) Stats() CollectionStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return CollectionStats{
+		Objects:   len(c.objects),
+		Memory:    c.counters.memory.usage(),
+		Evictions: c.counters.evictions.Load(),
+	}
+}
+
+func (c collection) Settings() config.CollectionConfig {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.settings.config
+}
+
+func (c collection) Configure(update config.CollectionUpdate) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.configure(update.Apply(c.settings.config))
+}
+
+// configure w/o lock
+func (c collection) configure(settings config.CollectionConfig) {
+	c.counters.memory.setLimit(settings.MaxMemory)
+	c.counters.keys.setLimit(int64(settings.MaxKeys))
+
+	if settings.EvictionPolicy != c.settings.config.EvictionPolicy {
+		// new evictor should know about all objects
+		c.settings.evictor = newEvictor(settings.EvictionPolicy)
+		if c.settings.evictor != nil {
+			for key, obj := range c.objects {
+				c.settings.evictor.touch(key, obj)
+			}
+		}
+	}
+	c.settings.config = settings
+}
+
+func (c collection) Drop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.objects {
+		c.delete(key)
+	}
+}
+
+// evict deletes one object chosen by evictor
+func (c collection) evict() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, _, ok := c.evictVictim()
+	return ok
+}
+
+// evictVictim w/o lock, returns evicted key and object
+func (c collection) evictVictim() (string, object.Object, bool) {
+	if c.settings.evictor == nil {
+		return "", nil, false
+	}
+
+	victim, ok := c.settings.evictor.victim(c)
+	if !ok {
+		return "", nil, false
+	}
+	evicted := c.objects[victim]
+	c.delete(victim)
+	c.counters.evictions.Add(1)
+	return victim, evicted, true
+}
+
+func (c collection) shardOf(string) collection {
+	return c
+}
+
+func (c collection) allShards() []collection {
+	return []collection{c}
+}
+
+// slide extends expiration of sliding object w/o new version, read isn't write,
+// read obj is returned if object has been deleted or replaced since it was read
+func (c collection) slide(key string, obj object.Object) object.Object {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	current, ok := c.objects[key]
+	if !ok || current.IsExpired() || !current.IsSliding() {
+		return obj
+	}
+
+	slid := current.Slide()
+	c.objects[key] = slid
+	c.expiry.set(key, slid.Expires())
+	return slid
+}
+
+// touch updates access metadata of object for evictor
+func (c collection) touch(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if obj, ok := c.objects[key]; ok && c.settings.evictor != nil {
+		obj.Touch()
+		c.settings.evictor.touch(key, obj)
+	}
+}
+
+// peek returns object w/o lock, nil for missing or expired object
+func (c collection) peek(key string) object.Object {
+	if obj, ok := c.objects[key]; ok && !obj.IsExpired() {
+		return obj
+	}
+	return nil
+}
+
+// delete object w/o lock
+func (c collection) delete(key string) {
+	obj, ok := c.objects[key]
+	if !ok {
+		return
+	}
+
+	delete(c.objects, key)
+	*c.index = c.index.Remove(key)
+	c.counters.memory.add(-objectSize(key, obj.Size()))
+	c.counters.keys.add(-1)
+	c.expiry.remove(key)
+	if c.settings.evictor != nil {
+		c.settings.evictor.remove(key)
+	}
+}
+
+// replace returns update func which replaces any current object
+func replace(obj object.Object) UpdateFunc {
+	return func(object.Object) (object.Object, error) {
+		return obj, nil
+	}
+}
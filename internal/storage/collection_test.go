@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -23,7 +24,7 @@ func TestCollection_Get(t *testing.T) {
 			name:       "shouldn't fail",
 			toAdd:      testObject,
 			getKey:     testKey,
-			wantObject: testObject,
+			wantObject: testObject.WithVersion(1),
 		},
 		{
 			name:      "no object",
@@ -80,3 +81,87 @@ func TestCollection_Delete(t *testing.T) {
 		})
 	}
 }
+
+func TestCollection_Update(t *testing.T) {
+	collection := NewCollection()
+	errTest := errors.ErrEmptyField("test")
+
+	tests := []struct {
+		name       string
+		update     UpdateFunc
+		wantData   []byte
+		wantError  error
+		wantExists bool
+	}{
+		{
+			name: "create",
+			update: func(current object.Object) (object.Object, error) {
+				assert.Nil(t, current)
+				return testObject, nil
+			},
+			wantData:   testObject.Binary(),
+			wantExists: true,
+		},
+		{
+			name: "error cancels update",
+			update: func(current object.Object) (object.Object, error) {
+				return nil, errTest
+			},
+			wantError:  errTest,
+			wantExists: true,
+		},
+		{
+			name: "replace",
+			update: func(current object.Object) (object.Object, error) {
+				data := append(current.Binary(), '2')
+				return object.New(data, object.WithoutTimeout()), nil
+			},
+			wantData:   []byte("12"),
+			wantExists: true,
+		},
+		{
+			name: "delete",
+			update: func(current object.Object) (object.Object, error) {
+				return nil, nil
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			obj, err := collection.Update(testKey, test.update)
+			assert.Equal(t, test.wantError, err)
+			if test.wantData != nil {
+				assert.Equal(t, test.wantData, obj.Binary())
+			}
+
+			_, err = collection.Get(testKey)
+			assert.Equal(t, test.wantExists, err == nil)
+		})
+	}
+}
+
+func TestCollection_GetSliding(t *testing.T) {
+	c := NewCollection().(collection)
+	sliding := object.RequestSettings{Data: []byte("1"), Timeout: 60, Sliding: 1}.New(0)
+	assert.Nil(t, c.Set(testKey, sliding))
+
+	// reads keep object alive longer than window
+	for i := 0; i < 3; i++ {
+		time.Sleep(500 * time.Millisecond)
+		obj, err := c.Get(testKey)
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(1), obj.Version())
+	}
+
+	// expiry index follows extended expiration
+	c.Refresh(context.Background())
+	obj, err := c.Get(testKey)
+	assert.Nil(t, err)
+	assert.Equal(t, obj.Expires(), c.expiry.items[0].expires)
+
+	// w/o reads object expires after window
+	time.Sleep(1100 * time.Millisecond)
+	c.Refresh(context.Background())
+	assert.Equal(t, 0, c.Stats().Objects)
+}
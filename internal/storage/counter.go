@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+// IncrementObject atomically adds increment to integer object and returns updated object,
+// missing object is created w 0 value and expiration settings,
+// existing object keeps its expiration if expiration settings are empty
+func IncrementObject(s Storage, collectionName, objectKey string, increment int64, expiration object.RequestSettings) (object.Object, error) {
+	return updateData(s, collectionName, objectKey, expiration, func(data []byte) ([]byte, error) {
+		return addInteger(data, increment, errors.ErrNotInteger(objectKey))
+	})
+}
+
+// addInteger adds increment to decimal data, nil data is 0,
+// notInteger is returned if data isn't an integer
+func addInteger(data []byte, increment int64, notInteger error) ([]byte, error) {
+	var value int64
+	if data != nil {
+		parsed, err := strconv.ParseInt(string(data), 10, 64)
+		if err != nil {
+			return nil, notInteger
+		}
+		value = parsed
+	}
+
+	if (increment > 0 && value > math.MaxInt64-increment) || (increment < 0 && value < math.MinInt64-increment) {
+		return nil, errors.ErrIncrementOverflow
+	}
+	return strconv.AppendInt(nil, value+increment, 10), nil
+}
+
+// IncrementFloatObject is IncrementObject for float values
+func IncrementFloatObject(s Storage, collectionName, objectKey string, increment float64, expiration object.RequestSettings) (object.Object, error) {
+	return updateData(s, collectionName, objectKey, expiration, func(data []byte) ([]byte, error) {
+		var value float64
+		if data != nil {
+			parsed, err := strconv.ParseFloat(string(data), 64)
+			if err != nil || math.IsNaN(parsed) || math.IsInf(parsed, 0) {
+				return nil, errors.ErrNotFloat(objectKey)
+			}
+			value = parsed
+		}
+
+		value += increment
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return nil, errors.ErrIncrementNaN
+		}
+		return strconv.AppendFloat(nil, value, 'f', -1, 64), nil
+	})
+}
@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+func TestIncrementObject(t *testing.T) {
+	testStorage := New(testConfig)
+	tests := []struct {
+		name      string
+		key       string
+		set       *object.RequestSettings
+		increment int64
+		wantData  string
+		wantError error
+	}{
+		{
+			name:      "missing object is created w 0",
+			key:       "new",
+			increment: 5,
+			wantData:  "5",
+		},
+		{
+			name:      "decrement existing",
+			key:       "new",
+			increment: -7,
+			wantData:  "-2",
+		},
+		{
+			name:      "not integer",
+			key:       "text",
+			set:       &object.RequestSettings{Data: []byte("text")},
+			increment: 1,
+			wantError: errors.ErrNotInteger("text"),
+		},
+		{
+			name:      "overflow",
+			key:       "max",
+			set:       &object.RequestSettings{Data: []byte("9223372036854775807")},
+			increment: 1,
+			wantError: errors.ErrIncrementOverflow,
+		},
+		{
+			name:      "underflow",
+			key:       "min",
+			set:       &object.RequestSettings{Data: []byte("-1")},
+			increment: math.MinInt64,
+			wantError: errors.ErrIncrementOverflow,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.set != nil {
+				require.Nil(t, setObject(testStorage, testCollection, test.key, *test.set))
+			}
+
+			obj, err := IncrementObject(testStorage, testCollection, test.key, test.increment, object.RequestSettings{})
+			require.Equal(t, test.wantError, err)
+			if err == nil {
+				assert.Equal(t, test.wantData, string(obj.Binary()))
+			}
+		})
+	}
+}
+
+func TestIncrementFloatObject(t *testing.T) {
+	testStorage := New(testConfig)
+
+	obj, err := IncrementFloatObject(testStorage, testCollection, "float", 1.5, object.RequestSettings{})
+	require.Nil(t, err)
+	assert.Equal(t, "1.5", string(obj.Binary()))
+
+	obj, err = IncrementFloatObject(testStorage, testCollection, "float", -0.25, object.RequestSettings{})
+	require.Nil(t, err)
+	assert.Equal(t, "1.25", string(obj.Binary()))
+
+	_, err = IncrementFloatObject(testStorage, testCollection, "float", math.Inf(1), object.RequestSettings{})
+	assert.Equal(t, errors.ErrIncrementNaN, err)
+
+	require.Nil(t, setObject(testStorage, testCollection, "text", object.RequestSettings{Data: []byte("text")}))
+	_, err = IncrementFloatObject(testStorage, testCollection, "text", 1, object.RequestSettings{})
+	assert.Equal(t, errors.ErrNotFloat("text"), err)
+}
+
+func TestIncrementObject_Expiration(t *testing.T) {
+	testStorage := New(testConfig)
+
+	created, err := IncrementObject(testStorage, testCollection, testKey, 1, object.RequestSettings{Timeout: 100})
+	require.Nil(t, err)
+
+	// existing expiration is kept
+	incremented, err := IncrementObject(testStorage, testCollection, testKey, 1, object.RequestSettings{})
+	require.Nil(t, err)
+	assert.Equal(t, created.Expires(), incremented.Expires())
+	assert.Greater(t, incremented.Version(), created.Version())
+
+	// or changed by expiration settings
+	timeless, err := IncrementObject(testStorage, testCollection, testKey, 1, object.RequestSettings{Timeless: true})
+	require.Nil(t, err)
+	assert.True(t, timeless.IsTimeless())
+	assert.Equal(t, "3", string(timeless.Binary()))
+	assert.True(t, time.Until(created.Expires()) > 90*time.Second)
+}
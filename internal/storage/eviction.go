@@ -0,0 +1,188 @@
+package storage
+
+import (
+	"container/list"
+	"math/rand"
+
+	"github.com/mustthink/go-storage-like-redis/config"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+// count of sampled objects for choosing victim by sampling policies
+const evictionSamples = 5
+
+type (
+	// evictor chooses object for eviction when collection memory is full,
+	// it isn't concurrent safe and should be guarded by collection lock
+	evictor interface {
+		// touch is called on every Set and Get of object
+		touch(key string, obj object.Object)
+		remove(key string)
+		victim(c collection) (key string, ok bool)
+	}
+
+	// lruEvictor evicts the least recently used object,
+	// w volatileOnly it tracks only objects w expiration time
+	lruEvictor struct {
+		order        *list.List
+		byKey        map[string]*list.Element
+		volatileOnly bool
+	}
+
+	// lfuEvictor evicts the least frequently used object of random sample
+	lfuEvictor struct {
+		keys *keySet
+	}
+
+	// volatileTTLEvictor evicts object w the nearest expiration time
+	volatileTTLEvictor struct{}
+
+	// volatileRandomEvictor evicts random object w expiration time
+	volatileRandomEvictor struct{}
+
+	// keySet is set of keys w random access
+	keySet struct {
+		keys    []string
+		indexes map[string]int
+	}
+)
+
+// newEvictor returns nil for noeviction policy
+func newEvictor(policy string) evictor {
+	switch policy {
+	case config.AllKeysLRU:
+		return newLRUEvictor(false)
+	case config.VolatileLRU:
+		return newLRUEvictor(true)
+	case config.AllKeysLFU:
+		return &lfuEvictor{keys: newKeySet()}
+	case config.VolatileTTL:
+		return volatileTTLEvictor{}
+	case config.VolatileRandom:
+		return volatileRandomEvictor{}
+	default:
+		return nil
+	}
+}
+
+func newLRUEvictor(volatileOnly bool) *lruEvictor {
+	return &lruEvictor{
+		order:        list.New(),
+		byKey:        make(map[string]*list.Element),
+		volatileOnly: volatileOnly,
+	}
+}
+
+func (e *lruEvictor) touch(key string, obj object.Object) {
+	if e.volatileOnly && obj.IsTimeless() {
+		e.remove(key)
+		return
+	}
+
+	if element, ok := e.byKey[key]; ok {
+		e.order.MoveToFront(element)
+		return
+	}
+	e.byKey[key] = e.order.PushFront(key)
+}
+
+func (e *lruEvictor) remove(key string) {
+	if element, ok := e.byKey[key]; ok {
+		e.order.Remove(element)
+		delete(e.byKey, key)
+	}
+}
+
+func (e *lruEvictor) victim(_ collection) (string, bool) {
+	oldest := e.order.Back()
+	if oldest == nil {
+		return "", false
+	}
+	return oldest.Value.(string), true
+}
+
+func (e *lfuEvictor) touch(key string, _ object.Object) {
+	e.keys.add(key)
+}
+
+func (e *lfuEvictor) remove(key string) {
+	e.keys.remove(key)
+}
+
+func (e *lfuEvictor) victim(c collection) (string, bool) {
+	// small set is checked entirely
+	sample := e.keys.keys
+	if e.keys.len() > evictionSamples {
+		sample = make([]string, evictionSamples)
+		for i := range sample {
+			sample[i] = e.keys.random()
+		}
+	}
+
+	var (
+		victim    string
+		frequency = -1
+	)
+	for _, key := range sample {
+		if sampled := int(c.objects[key].Frequency()); frequency == -1 || sampled < frequency {
+			victim, frequency = key, sampled
+		}
+	}
+	return victim, frequency != -1
+}
+
+// timeless objects aren't in expiry index, so volatile evictors use it
+func (volatileTTLEvictor) touch(string, object.Object) {}
+
+func (volatileTTLEvictor) remove(string) {}
+
+func (volatileTTLEvictor) victim(c collection) (string, bool) {
+	return c.expiry.nearest()
+}
+
+func (volatileRandomEvictor) touch(string, object.Object) {}
+
+func (volatileRandomEvictor) remove(string) {}
+
+func (volatileRandomEvictor) victim(c collection) (string, bool) {
+	if c.expiry.len() == 0 {
+		return "", false
+	}
+	return c.expiry.items[rand.Intn(c.expiry.len())].key, true
+}
+
+func newKeySet() *keySet {
+	return &keySet{
+		indexes: make(map[string]int),
+	}
+}
+
+func (s *keySet) add(key string) {
+	if _, ok := s.indexes[key]; ok {
+		return
+	}
+	s.indexes[key] = len(s.keys)
+	s.keys = append(s.keys, key)
+}
+
+func (s *keySet) remove(key string) {
+	index, ok := s.indexes[key]
+	if !ok {
+		return
+	}
+
+	// move the last key to the place of removed one
+	last := s.keys[len(s.keys)-1]
+	s.keys[index] = last
+	s.indexes[last] = index
+	s.keys = s.keys[:len(s.keys)-1]
+	delete(s.indexes, key)
+}
+
+func (s *keySet) random() string {
+	return s.keys[rand.Intn(len(s.keys))]
+}
+
+func (s *keySet) len() int {
+	return len(s.keys)
+}
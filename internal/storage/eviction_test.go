@@ -0,0 +1,189 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mustthink/go-storage-like-redis/config"
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+// every object w one byte key and one byte data uses 2 bytes
+var smallObject = object.New([]byte("1"), object.WithoutTimeout())
+
+func TestCollection_SetNoEviction(t *testing.T) {
+	c := NewCollection(withConfig(config.CollectionConfig{MaxMemory: 4}))
+
+	require.Nil(t, c.Set("1", smallObject))
+	require.Nil(t, c.Set("2", smallObject))
+	// overwriting doesn't need more memory
+	require.Nil(t, c.Set("2", smallObject))
+
+	err := c.Set("3", smallObject)
+	assert.Equal(t, errors.ErrOutOfMemory, err)
+	assert.Equal(t, CollectionStats{Objects: 2, Memory: 4}, c.Stats())
+
+	require.Nil(t, c.Delete("1"))
+	assert.Nil(t, c.Set("3", smallObject))
+}
+
+func TestCollection_SetAllKeysLRU(t *testing.T) {
+	c := NewCollection(withConfig(config.CollectionConfig{MaxMemory: 6, EvictionPolicy: config.AllKeysLRU}))
+
+	require.Nil(t, c.Set("1", smallObject))
+	require.Nil(t, c.Set("2", smallObject))
+	require.Nil(t, c.Set("3", smallObject))
+
+	// "1" becomes the most recently used
+	_, err := c.Get("1")
+	require.Nil(t, err)
+
+	require.Nil(t, c.Set("4", smallObject))
+	_, err = c.Get("2")
+	assert.Equal(t, errors.ErrNoObject("2"), err)
+
+	// object bigger than the whole memory fails w/o evictions
+	err = c.Set("5", object.New(make([]byte, 10), object.WithoutTimeout()))
+	assert.ErrorIs(t, err, errors.ErrOutOfMemory)
+	assert.Equal(t, CollectionStats{Objects: 3, Memory: 6, Evictions: 1}, c.Stats())
+}
+
+func TestShardedCollection_SetTooLarge(t *testing.T) {
+	c := NewShardedCollection(4, withConfig(config.CollectionConfig{MaxMemory: 20, EvictionPolicy: config.AllKeysLRU}))
+	for _, key := range []string{"1", "2", "3", "4", "5"} {
+		require.Nil(t, c.Set(key, smallObject))
+	}
+
+	err := c.Set("6", object.New(make([]byte, 20), object.WithoutTimeout()))
+	assert.ErrorIs(t, err, errors.ErrOutOfMemory)
+	assert.Equal(t, CollectionStats{Objects: 5, Memory: 10}, c.Stats())
+}
+
+func TestStorage_MaxMemory(t *testing.T) {
+	storageConfig := testConfig
+	storageConfig.MaxCollectionsCount = 2
+	storageConfig.MaxMemory = 4
+	testStorage := New(storageConfig)
+
+	require.Nil(t, testStorage.NewCollection("test", config.CollectionConfig{}))
+	require.Nil(t, setObject(testStorage, "test", "1", testRequestSettings))
+	require.Nil(t, setObject(testStorage, "", "1", testRequestSettings))
+
+	err := setObject(testStorage, "", "2", testRequestSettings)
+	assert.Equal(t, errors.ErrOutOfMemory, err)
+	assert.Equal(t, int64(4), testStorage.Stats().Memory)
+
+	// memory of deleted collection is released
+	require.Nil(t, testStorage.DeleteCollection("test"))
+	assert.Nil(t, setObject(testStorage, "", "2", testRequestSettings))
+}
+
+func TestLRUEvictor(t *testing.T) {
+	e := newLRUEvictor(false)
+	_, ok := e.victim(collection{})
+	assert.False(t, ok)
+
+	e.touch("1", smallObject)
+	e.touch("2", smallObject)
+	e.touch("3", smallObject)
+	e.touch("1", smallObject)
+	e.remove("2")
+
+	victim, ok := e.victim(collection{})
+	assert.True(t, ok)
+	assert.Equal(t, "3", victim)
+}
+
+func TestCollection_SetVolatilePolicies(t *testing.T) {
+	timeObject := func(timeout time.Duration) object.Object {
+		return object.New([]byte("1"), object.WithTimeout(timeout))
+	}
+
+	tests := []struct {
+		name        string
+		policy      string
+		toAdd       map[string]object.Object
+		wantEvicted []string
+		wantError   error
+	}{
+		{
+			name:   "volatile-ttl evicts the nearest expiration",
+			policy: config.VolatileTTL,
+			toAdd: map[string]object.Object{
+				"1": timeObject(time.Hour),
+				"2": timeObject(time.Minute),
+				"3": smallObject,
+			},
+			wantEvicted: []string{"2"},
+		},
+		{
+			name:   "volatile-lru evicts the least recently used w expiration",
+			policy: config.VolatileLRU,
+			toAdd: map[string]object.Object{
+				"1": smallObject,
+				"2": timeObject(time.Hour),
+				"3": timeObject(time.Hour),
+			},
+			wantEvicted: []string{"2"},
+		},
+		{
+			name:   "volatile-random evicts object w expiration",
+			policy: config.VolatileRandom,
+			toAdd: map[string]object.Object{
+				"1": smallObject,
+				"2": timeObject(time.Hour),
+				"3": smallObject,
+			},
+			wantEvicted: []string{"2"},
+		},
+		{
+			name:   "volatile policy doesn't evict timeless objects",
+			policy: config.VolatileTTL,
+			toAdd: map[string]object.Object{
+				"1": smallObject,
+				"2": smallObject,
+				"3": smallObject,
+			},
+			wantError: errors.ErrOutOfMemory,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := NewCollection(withConfig(config.CollectionConfig{MaxMemory: 6, EvictionPolicy: test.policy}))
+			for _, key := range []string{"1", "2", "3"} {
+				require.Nil(t, c.Set(key, test.toAdd[key]))
+			}
+
+			err := c.Set("4", smallObject)
+			assert.Equal(t, test.wantError, err)
+			for _, key := range test.wantEvicted {
+				_, err := c.Get(key)
+				assert.Equal(t, errors.ErrNoObject(key), err)
+			}
+		})
+	}
+}
+
+func TestCollection_SetAllKeysLFU(t *testing.T) {
+	c := NewCollection(withConfig(config.CollectionConfig{MaxMemory: 6, EvictionPolicy: config.AllKeysLFU})).(collection)
+
+	for _, key := range []string{"1", "2", "3"} {
+		require.Nil(t, c.Set(key, object.New([]byte("1"), object.WithoutTimeout())))
+	}
+
+	// every object except "2" becomes more frequently used
+	for i := 0; i < 1000; i++ {
+		c.Get("1")
+		c.Get("3")
+	}
+
+	require.Nil(t, c.Set("4", smallObject))
+	_, err := c.Get("2")
+	assert.Equal(t, errors.ErrNoObject("2"), err)
+	assert.Equal(t, uint64(1), c.Stats().Evictions)
+}
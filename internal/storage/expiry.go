@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"container/heap"
+	"time"
+)
+
+type (
+	// expiryIndex is min-heap of object keys ordered by expiration time,
+	// timeless objects aren't indexed.
+	// It isn't concurrent safe and should be guarded by collection lock
+	expiryIndex struct {
+		items expiryHeap
+		byKey map[string]*expiryItem
+	}
+
+	expiryItem struct {
+		key     string
+		expires time.Time
+		index   int
+	}
+
+	expiryHeap []*expiryItem
+)
+
+func newExpiryIndex() *expiryIndex {
+	return &expiryIndex{
+		byKey: make(map[string]*expiryItem),
+	}
+}
+
+// set add key to index or move it if expiration time changed
+func (e *expiryIndex) set(key string, expires time.Time) {
+	if item, ok := e.byKey[key]; ok {
+		item.expires = expires
+		heap.Fix(&e.items, item.index)
+		return
+	}
+
+	item := &expiryItem{
+		key:     key,
+		expires: expires,
+	}
+	heap.Push(&e.items, item)
+	e.byKey[key] = item
+}
+
+func (e *expiryIndex) remove(key string) {
+	item, ok := e.byKey[key]
+	if !ok {
+		return
+	}
+
+	heap.Remove(&e.items, item.index)
+	delete(e.byKey, key)
+}
+
+// due returns key w the earliest expiration time if it's expired at now
+func (e *expiryIndex) due(now time.Time) (string, bool) {
+	if len(e.items) == 0 || !e.items[0].expires.Before(now) {
+		return "", false
+	}
+	return e.items[0].key, true
+}
+
+// nearest returns key w the earliest expiration time
+func (e *expiryIndex) nearest() (string, bool) {
+	if len(e.items) == 0 {
+		return "", false
+	}
+	return e.items[0].key, true
+}
+
+func (e *expiryIndex) len() int {
+	return len(e.items)
+}
+
+// heap.Interface implementation
+func (h expiryHeap) Len() int { return len(h) }
+
+func (h expiryHeap) Less(i, j int) bool { return h[i].expires.Before(h[j].expires) }
+
+func (h expiryHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *expiryHeap) Push(x any) {
+	item := x.(*expiryItem)
+	item.index = len(*h)
+	*h = append(*h, item)
+}
+
+func (h *expiryHeap) Pop() any {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return item
+}
@@ -0,0 +1,136 @@
+package storage
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mustthink/go-storage-like-redis/config"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+func TestExpiryIndex(t *testing.T) {
+	now := time.Now()
+	index := newExpiryIndex()
+
+	index.set("3", now.Add(-time.Second))
+	index.set("1", now.Add(-3*time.Second))
+	index.set("2", now.Add(-2*time.Second))
+	index.set("4", now.Add(time.Hour))
+	// moved to the future
+	index.set("2", now.Add(2*time.Hour))
+
+	var gotKeys []string
+	for {
+		key, ok := index.due(now)
+		if !ok {
+			break
+		}
+		gotKeys = append(gotKeys, key)
+		index.remove(key)
+	}
+
+	assert.Equal(t, []string{"1", "3"}, gotKeys)
+	assert.Equal(t, 2, index.len())
+}
+
+func TestCollection_Refresh(t *testing.T) {
+	tests := []struct {
+		name        string
+		expired     int
+		alive       int
+		cancelled   bool
+		wantObjects int
+	}{
+		{
+			name:        "nothing expired",
+			alive:       10,
+			wantObjects: 10,
+		},
+		{
+			name:        "more expired than batch",
+			expired:     refreshBatch*3 + 1,
+			alive:       10,
+			wantObjects: 10,
+		},
+		{
+			name:        "cancelled context",
+			expired:     10,
+			alive:       10,
+			cancelled:   true,
+			wantObjects: 20,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := NewCollection().(collection)
+			for i := 0; i < test.expired; i++ {
+				c.Set("expired"+strconv.Itoa(i), object.New(nil, object.WithTimeout(-time.Second)))
+			}
+			for i := 0; i < test.alive; i++ {
+				c.Set("alive"+strconv.Itoa(i), object.New(nil, object.WithTimeout(time.Hour)))
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			if test.cancelled {
+				cancel()
+			}
+			defer cancel()
+
+			c.Refresh(ctx)
+			assert.Len(t, c.objects, test.wantObjects)
+			assert.Equal(t, test.wantObjects, c.expiry.len())
+		})
+	}
+}
+
+func TestCollection_SampleExpired(t *testing.T) {
+	c := NewCollection().(collection)
+	for i := 0; i < 100; i++ {
+		c.Set("expired"+strconv.Itoa(i), object.New(nil, object.WithTimeout(-time.Second)))
+	}
+
+	sampled, expired := c.SampleExpired(20)
+	assert.Equal(t, 20, sampled)
+	assert.Equal(t, 20, expired)
+	assert.Len(t, c.objects, 80)
+
+	empty := NewCollection()
+	sampled, expired = empty.SampleExpired(20)
+	assert.Zero(t, sampled)
+	assert.Zero(t, expired)
+}
+
+func TestStorage_sampleCollection(t *testing.T) {
+	testStorage := New(testConfig).(*storage)
+	testStorage.config.Expiration = config.ExpirationConfig{
+		Strategy:   config.ExpirationSampling,
+		SampleSize: 20,
+		Threshold:  0.25,
+		Budget:     1000,
+	}
+
+	c := NewCollection().(collection)
+	for i := 0; i < 100; i++ {
+		c.Set("expired"+strconv.Itoa(i), object.New(nil, object.WithTimeout(-time.Second)))
+	}
+
+	// all sampled objects are expired, so sampling repeats until collection is empty
+	testStorage.sampleCollection(c, time.Now().Add(time.Second))
+	stats := testStorage.Stats().Expiration
+	assert.Equal(t, uint64(100), stats.Expired)
+	assert.Equal(t, uint64(100), stats.Sampled)
+	assert.Empty(t, c.objects)
+
+	// nothing expired, so sampling stops after one cycle
+	for i := 0; i < 10; i++ {
+		c.Set("alive"+strconv.Itoa(i), object.New(nil, object.WithTimeout(time.Hour)))
+	}
+	testStorage.sampleCollection(c, time.Now().Add(time.Second))
+	assert.Equal(t, stats.Cycles+1, testStorage.Stats().Expiration.Cycles)
+	assert.Len(t, c.objects, 10)
+}
@@ -0,0 +1,49 @@
+package storage
+
+import "github.com/mustthink/go-storage-like-redis/internal/errors"
+
+// max count of objects read per one lock of collection while exporting
+const exportPageSize = 1000
+
+// ExportFunc gets every exported object, error stops export
+type ExportFunc func(entry Entry) error
+
+// ExportCollection passes objects after cursor to export func page by page in scan order,
+// every page seeks to its cursor by index of keys, so page costs O(log n) and its size, not the whole collection,
+// page could be empty for expired objects, export goes on until cursor is empty,
+// count limits objects of one call, 0 - all objects,
+// returns cursor of the next objects, empty if collection is exported entirely
+func ExportCollection(s Storage, collectionName, cursor string, count int, export ExportFunc) (string, error) {
+	if count < 0 {
+		return "", errors.ErrNegativeField("count")
+	}
+
+	collection, err := s.GetCollection(collectionName)
+	if err != nil {
+		return "", err
+	}
+
+	for exported := 0; count == 0 || exported < count; {
+		pageSize := exportPageSize
+		if count > 0 {
+			pageSize = min(pageSize, count-exported)
+		}
+
+		entries, next, err := collection.Scan(cursor, pageSize, "")
+		if err != nil {
+			return "", err
+		}
+		for _, entry := range entries {
+			if err := export(entry); err != nil {
+				return "", err
+			}
+		}
+
+		exported += len(entries)
+		cursor = next
+		if cursor == "" {
+			break
+		}
+	}
+	return cursor, nil
+}
@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+func TestExportCollection(t *testing.T) {
+	testStorage := New(testConfig)
+	for i := 0; i < exportPageSize+10; i++ {
+		require.Nil(t, setObject(testStorage, testCollection, strconv.Itoa(i), testRequestSettings))
+	}
+
+	var exported []Entry
+	export := func(entry Entry) error {
+		exported = append(exported, entry)
+		return nil
+	}
+
+	// all objects in several pages
+	next, err := ExportCollection(testStorage, testCollection, "", 0, export)
+	require.Nil(t, err)
+	assert.Empty(t, next)
+	assert.Len(t, exported, exportPageSize+10)
+
+	// count limits objects of call
+	exported = nil
+	next, err = ExportCollection(testStorage, testCollection, "", 7, export)
+	require.Nil(t, err)
+	assert.Len(t, exported, 7)
+	next, err = ExportCollection(testStorage, testCollection, next, exportPageSize+10, export)
+	require.Nil(t, err)
+	assert.Empty(t, next)
+	assert.Len(t, exported, exportPageSize+10)
+
+	_, err = ExportCollection(testStorage, testCollection, "", -1, export)
+	assert.Equal(t, errors.ErrNegativeField("count"), err)
+
+	// error of export func stops export
+	_, err = ExportCollection(testStorage, testCollection, "", 0, func(Entry) error { return errors.ErrOutOfMemory })
+	assert.Equal(t, errors.ErrOutOfMemory, err)
+}
+
+func TestExportCollection_EmptyPages(t *testing.T) {
+	testStorage := New(testConfig)
+	collection, err := testStorage.GetCollection(testCollection)
+	require.Nil(t, err)
+
+	// expired objects before live ones make empty pages
+	expired := object.New(nil, object.WithTimeout(-1))
+	for i := 0; i < exportPageSize*scanExaminedFactor+10; i++ {
+		require.Nil(t, collection.Set(fmt.Sprintf("a%06d", i), expired))
+	}
+	require.Nil(t, setObject(testStorage, testCollection, "b", testRequestSettings))
+
+	var exported []string
+	next, err := ExportCollection(testStorage, testCollection, "", 0, func(entry Entry) error {
+		exported = append(exported, entry.Key)
+		return nil
+	})
+	require.Nil(t, err)
+	assert.Empty(t, next)
+	assert.Equal(t, []string{"b"}, exported)
+}
+
+func TestExportCollection_Sliding(t *testing.T) {
+	storageConfig := testConfig
+	storageConfig.DefaultTTL = 100
+	testStorage := New(storageConfig)
+	require.Nil(t, setObject(testStorage, testCollection, testKey, object.RequestSettings{Data: []byte("1"), Sliding: 1}))
+
+	// export doesn't extend sliding window
+	time.Sleep(300 * time.Millisecond)
+	_, err := ExportCollection(testStorage, testCollection, "", 0, func(Entry) error { return nil })
+	require.Nil(t, err)
+
+	ttl, err := ObjectTTL(testStorage, testCollection, testKey)
+	require.Nil(t, err)
+	assert.Less(t, ttl, 800*time.Millisecond)
+}
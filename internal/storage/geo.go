@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+// GeoAdd adds members w locations to sorted set of geo members or updates locations by options
+// and returns count of added members, missing sorted set is created w expiration settings
+func GeoAdd(s Storage, collectionName, objectKey string, locations map[string]object.GeoPoint, options object.ZAddOptions, expiration object.RequestSettings) (int, error) {
+	if len(locations) == 0 {
+		return 0, errors.ErrEmptyField("locations")
+	}
+
+	members := make([]object.ScoredMember, 0, len(locations))
+	for member, point := range locations {
+		score, err := object.GeoScore(point)
+		if err != nil {
+			return 0, err
+		}
+		members = append(members, object.ScoredMember{Member: member, Score: score})
+	}
+	return ZSetAdd(s, collectionName, objectKey, members, options, expiration)
+}
+
+// GeoPos returns locations of members in the same order, nil for missing member
+func GeoPos(s Storage, collectionName, objectKey string, members []string) ([]*object.GeoPoint, error) {
+	zset, err := viewValue[object.ZSet](s, collectionName, objectKey)
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]*object.GeoPoint, len(members))
+	for i, member := range members {
+		if score, ok := zset.Score(member); ok {
+			point := object.GeoPointOf(score)
+			points[i] = &point
+		}
+	}
+	return points, nil
+}
+
+// GeoDist returns distance between members in unit and whether both members exist
+func GeoDist(s Storage, collectionName, objectKey, from, to string, unit object.GeoUnit) (float64, bool, error) {
+	meters, err := unit.Meters()
+	if err != nil {
+		return 0, false, err
+	}
+
+	zset, err := viewValue[object.ZSet](s, collectionName, objectKey)
+	if err != nil {
+		return 0, false, err
+	}
+
+	fromScore, fromOK := zset.Score(from)
+	toScore, toOK := zset.Score(to)
+	if !fromOK || !toOK {
+		return 0, false, nil
+	}
+	return object.GeoDistance(object.GeoPointOf(fromScore), object.GeoPointOf(toScore)) / meters, true, nil
+}
+
+// GeoSearch returns up to count members in area ordered by distance, 0 count - all members,
+// center of area is location of member if it isn't empty, lengths of query and distances of results are in unit
+func GeoSearch(s Storage, collectionName, objectKey, member string, query object.GeoQuery, unit object.GeoUnit, count int, desc bool) ([]object.GeoResult, error) {
+	meters, err := unit.Meters()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case count < 0:
+		return nil, errors.ErrNegativeField("count")
+	case query.Radius < 0, query.Width < 0, query.Height < 0:
+		return nil, errors.ErrNegativeField("radius, width or height")
+	case query.Radius > 0 && (query.Width > 0 || query.Height > 0):
+		return nil, errors.ErrConflictFields("radius", "width and height")
+	case query.Radius == 0 && (query.Width == 0 || query.Height == 0):
+		return nil, errors.ErrEmptyField("radius or width and height")
+	}
+
+	zset, err := viewValue[object.ZSet](s, collectionName, objectKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if member != "" {
+		score, ok := zset.Score(member)
+		if !ok {
+			return nil, errors.ErrNoMember(member)
+		}
+		query.Center = object.GeoPointOf(score)
+	} else if _, err := object.GeoScore(query.Center); err != nil {
+		return nil, err
+	}
+
+	query.Radius, query.Width, query.Height = query.Radius*meters, query.Width*meters, query.Height*meters
+	results := zset.GeoSearch(query)
+	if desc {
+		for i, j := 0, len(results)-1; i < j; i, j = i+1, j-1 {
+			results[i], results[j] = results[j], results[i]
+		}
+	}
+	if count > 0 && len(results) > count {
+		results = results[:count]
+	}
+
+	for i := range results {
+		results[i].Distance /= meters
+	}
+	return results, nil
+}
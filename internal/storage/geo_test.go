@@ -0,0 +1,79 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+func TestGeo(t *testing.T) {
+	testStorage := New(testConfig)
+	locations := map[string]object.GeoPoint{
+		"Palermo": {Longitude: 13.361389, Latitude: 38.115556},
+		"Catania": {Longitude: 15.087269, Latitude: 37.502669},
+	}
+
+	added, err := GeoAdd(testStorage, testCollection, "sicily", locations, object.ZAddOptions{}, object.RequestSettings{})
+	require.Nil(t, err)
+	assert.Equal(t, 2, added)
+
+	positions, err := GeoPos(testStorage, testCollection, "sicily", []string{"Palermo", "unknown"})
+	require.Nil(t, err)
+	require.Len(t, positions, 2)
+	assert.InDelta(t, 13.361389, positions[0].Longitude, 0.00001)
+	assert.InDelta(t, 38.115556, positions[0].Latitude, 0.00001)
+	assert.Nil(t, positions[1])
+
+	distance, ok, err := GeoDist(testStorage, testCollection, "sicily", "Palermo", "Catania", object.GeoUnitKilometers)
+	require.Nil(t, err)
+	assert.True(t, ok)
+	assert.InDelta(t, 166.2742, distance, 0.001)
+	_, ok, err = GeoDist(testStorage, testCollection, "sicily", "Palermo", "unknown", "")
+	require.Nil(t, err)
+	assert.False(t, ok)
+
+	results, err := GeoSearch(testStorage, testCollection, "sicily", "", object.GeoQuery{Center: object.GeoPoint{Longitude: 15, Latitude: 37}, Radius: 200}, object.GeoUnitKilometers, 0, true)
+	require.Nil(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "Palermo", results[0].Member)
+	assert.InDelta(t, 190.4424, results[0].Distance, 0.001)
+
+	// search around member, member itself is found
+	results, err = GeoSearch(testStorage, testCollection, "sicily", "Palermo", object.GeoQuery{Width: 10, Height: 10}, object.GeoUnitKilometers, 1, false)
+	require.Nil(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "Palermo", results[0].Member)
+
+	// geo members are sorted set members
+	removed, err := ZSetRemove(testStorage, testCollection, "sicily", []string{"Palermo"})
+	require.Nil(t, err)
+	assert.Equal(t, 1, removed)
+
+	tests := []struct {
+		name    string
+		member  string
+		query   object.GeoQuery
+		unit    object.GeoUnit
+		wantErr error
+	}{
+		{name: "missing member", member: "Palermo", query: object.GeoQuery{Radius: 1}, wantErr: errors.ErrNoMember("Palermo")},
+		{name: "radius and box", query: object.GeoQuery{Radius: 1, Width: 1, Height: 1}, wantErr: errors.ErrConflictFields("radius", "width and height")},
+		{name: "no area", query: object.GeoQuery{Width: 1}, wantErr: errors.ErrEmptyField("radius or width and height")},
+		{name: "unknown unit", query: object.GeoQuery{Radius: 1}, unit: "yd", wantErr: errors.ErrUnknownValue("unit", "yd")},
+		{name: "invalid center", query: object.GeoQuery{Center: object.GeoPoint{Latitude: 90}, Radius: 1}, wantErr: errors.ErrInvalidCoordinates(0, 90)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := GeoSearch(testStorage, testCollection, "sicily", test.member, test.query, test.unit, 0, false)
+			assert.Equal(t, test.wantErr, err)
+		})
+	}
+
+	_, err = GeoAdd(testStorage, testCollection, "sicily", map[string]object.GeoPoint{"Pole": {Latitude: 89}}, object.ZAddOptions{}, object.RequestSettings{})
+	assert.Equal(t, errors.ErrInvalidCoordinates(0, 89), err)
+}
@@ -0,0 +1,126 @@
+package storage
+
+import "github.com/mustthink/go-storage-like-redis/internal/errors"
+
+// validateGlob checks that every char class of pattern is closed
+func validateGlob(pattern string) error {
+	for p := 0; p < len(pattern); p++ {
+		switch pattern[p] {
+		case '\\':
+			p++
+		case '[':
+			end := classEnd(pattern, p)
+			if end == -1 {
+				return errors.ErrInvalidPattern(pattern)
+			}
+			p = end - 1
+		}
+	}
+	return nil
+}
+
+// matchGlob reports whether s matches Redis-like glob pattern:
+// * any sequence, ? any byte, [abc], [^a-z] byte classes, \ escapes next byte,
+// pattern should be validated by validateGlob
+func matchGlob(pattern, s string) bool {
+	var (
+		p, i  int
+		starP = -1
+		starI int
+	)
+	for i < len(s) {
+		if p < len(pattern) {
+			switch pattern[p] {
+			case '*':
+				// remember star position for backtracking
+				starP, starI = p, i
+				p++
+				continue
+			case '?':
+				p, i = p+1, i+1
+				continue
+			case '[':
+				if matchClass(pattern[p:classEnd(pattern, p)], s[i]) {
+					p, i = classEnd(pattern, p), i+1
+					continue
+				}
+			case '\\':
+				literal, next := byte('\\'), p+1
+				if p+1 < len(pattern) {
+					literal, next = pattern[p+1], p+2
+				}
+				if literal == s[i] {
+					p, i = next, i+1
+					continue
+				}
+			default:
+				if pattern[p] == s[i] {
+					p, i = p+1, i+1
+					continue
+				}
+			}
+		}
+
+		// mismatch, star consumes one more byte
+		if starP == -1 {
+			return false
+		}
+		starI++
+		p, i = starP+1, starI
+	}
+
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
+
+// classEnd returns index after closing bracket of class started at p or -1
+func classEnd(pattern string, p int) int {
+	for p++; p < len(pattern); p++ {
+		switch pattern[p] {
+		case '\\':
+			p++
+		case ']':
+			return p + 1
+		}
+	}
+	return -1
+}
+
+// matchClass reports whether b matches class like [^a-z\]]
+func matchClass(class string, b byte) bool {
+	// trim brackets
+	class = class[1 : len(class)-1]
+	negate := len(class) > 0 && (class[0] == '^' || class[0] == '!')
+	if negate {
+		class = class[1:]
+	}
+
+	matched := false
+	for j := 0; j < len(class); j++ {
+		low := class[j]
+		if low == '\\' && j+1 < len(class) {
+			j++
+			low = class[j]
+		}
+
+		high := low
+		if j+2 < len(class) && class[j+1] == '-' {
+			high = class[j+2]
+			if high == '\\' && j+3 < len(class) {
+				j++
+				high = class[j+2]
+			}
+			j += 2
+		}
+		if low > high {
+			low, high = high, low
+		}
+
+		if low <= b && b <= high {
+			matched = true
+		}
+	}
+	return matched != negate
+}
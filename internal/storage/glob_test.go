@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		key     string
+		want    bool
+	}{
+		{pattern: "*", key: "", want: true},
+		{pattern: "*", key: "user:1", want: true},
+		{pattern: "user:*", key: "user:1", want: true},
+		{pattern: "user:*", key: "session:1", want: false},
+		{pattern: "*:1", key: "user:1", want: true},
+		{pattern: "u*r*1", key: "user:1", want: true},
+		{pattern: "h?llo", key: "hello", want: true},
+		{pattern: "h?llo", key: "hllo", want: false},
+		{pattern: "h[ae]llo", key: "hallo", want: true},
+		{pattern: "h[ae]llo", key: "hillo", want: false},
+		{pattern: "h[^e]llo", key: "hallo", want: true},
+		{pattern: "h[^e]llo", key: "hello", want: false},
+		{pattern: "h[a-c]llo", key: "hbllo", want: true},
+		{pattern: "h[a-c]llo", key: "hdllo", want: false},
+		{pattern: `h\*llo`, key: "h*llo", want: true},
+		{pattern: `h\*llo`, key: "hello", want: false},
+		{pattern: `a[\]]`, key: "a]", want: true},
+		{pattern: `a\`, key: `a\`, want: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.pattern+" "+test.key, func(t *testing.T) {
+			assert.Nil(t, validateGlob(test.pattern))
+			assert.Equal(t, test.want, matchGlob(test.pattern, test.key))
+		})
+	}
+
+	assert.Equal(t, errors.ErrInvalidPattern("h[ello"), validateGlob("h[ello"))
+}
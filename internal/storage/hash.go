@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+// HashSet sets values of fields and returns count of added fields,
+// missing hash is created w expiration settings
+func HashSet(s Storage, collectionName, objectKey string, values map[string][]byte, expiration object.RequestSettings) (int, error) {
+	if len(values) == 0 {
+		return 0, errors.ErrEmptyField("values")
+	}
+
+	var added int
+	_, err := updateValue(s, collectionName, objectKey, expiration, func(hash object.Hash) (object.Hash, error) {
+		hash, added = hash.Set(values)
+		return hash, nil
+	})
+	return added, err
+}
+
+// HashGet returns value of field, nil if field doesn't exist
+func HashGet(s Storage, collectionName, objectKey, field string) ([]byte, error) {
+	hash, err := viewValue[object.Hash](s, collectionName, objectKey)
+	if err != nil {
+		return nil, err
+	}
+
+	value, _ := hash.Get(field)
+	return value, nil
+}
+
+// HashMultiGet returns values of fields in the same order, nil for missing field
+func HashMultiGet(s Storage, collectionName, objectKey string, fields []string) ([][]byte, error) {
+	hash, err := viewValue[object.Hash](s, collectionName, objectKey)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([][]byte, len(fields))
+	for i, field := range fields {
+		values[i], _ = hash.Get(field)
+	}
+	return values, nil
+}
+
+// HashDelete deletes fields and returns count of deleted fields, hash w/o fields is deleted
+func HashDelete(s Storage, collectionName, objectKey string, fields []string) (int, error) {
+	var deleted int
+	_, err := updateValue(s, collectionName, objectKey, object.RequestSettings{}, func(hash object.Hash) (object.Hash, error) {
+		hash, deleted = hash.Delete(fields...)
+		if deleted == 0 {
+			return hash, SkipUpdate
+		}
+		return hash, nil
+	})
+	return deleted, err
+}
+
+// HashGetAll returns all fields w values
+func HashGetAll(s Storage, collectionName, objectKey string) (map[string][]byte, error) {
+	hash, err := viewValue[object.Hash](s, collectionName, objectKey)
+	if err != nil {
+		return nil, err
+	}
+	return hash.Fields(), nil
+}
+
+// HashIncrement atomically adds increment to integer field and returns new value,
+// missing field is created w 0 value
+func HashIncrement(s Storage, collectionName, objectKey, field string, increment int64, expiration object.RequestSettings) ([]byte, error) {
+	var value []byte
+	_, err := updateValue(s, collectionName, objectKey, expiration, func(hash object.Hash) (object.Hash, error) {
+		current, _ := hash.Get(field)
+		incremented, err := addInteger(current, increment, errors.ErrFieldNotInteger(field))
+		if err != nil {
+			return hash, err
+		}
+
+		value = incremented
+		hash, _ = hash.Set(map[string][]byte{field: value})
+		return hash, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func HashExists(s Storage, collectionName, objectKey, field string) (bool, error) {
+	hash, err := viewValue[object.Hash](s, collectionName, objectKey)
+	if err != nil {
+		return false, err
+	}
+
+	_, ok := hash.Get(field)
+	return ok, nil
+}
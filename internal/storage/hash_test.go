@@ -0,0 +1,85 @@
+package storage
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+func TestHash(t *testing.T) {
+	testStorage := New(testConfig)
+	expiration := object.RequestSettings{Timeout: 100}
+
+	added, err := HashSet(testStorage, testCollection, "user", map[string][]byte{"name": []byte("john"), "age": []byte("30")}, expiration)
+	require.Nil(t, err)
+	assert.Equal(t, 2, added)
+
+	value, err := HashGet(testStorage, testCollection, "user", "name")
+	require.Nil(t, err)
+	assert.Equal(t, []byte("john"), value)
+
+	values, err := HashMultiGet(testStorage, testCollection, "user", []string{"age", "unknown"})
+	require.Nil(t, err)
+	assert.Equal(t, [][]byte{[]byte("30"), nil}, values)
+
+	value, err = HashIncrement(testStorage, testCollection, "user", "age", 2, object.RequestSettings{})
+	require.Nil(t, err)
+	assert.Equal(t, []byte("32"), value)
+	_, err = HashIncrement(testStorage, testCollection, "user", "name", 1, object.RequestSettings{})
+	assert.Equal(t, errors.ErrFieldNotInteger("name"), err)
+
+	exists, err := HashExists(testStorage, testCollection, "user", "age")
+	require.Nil(t, err)
+	assert.True(t, exists)
+
+	// hash keeps expiration of creation
+	ttl, err := ObjectTTL(testStorage, testCollection, "user")
+	require.Nil(t, err)
+	assert.Greater(t, ttl.Seconds(), float64(90))
+
+	deleted, err := HashDelete(testStorage, testCollection, "user", []string{"age"})
+	require.Nil(t, err)
+	assert.Equal(t, 1, deleted)
+
+	fields, err := HashGetAll(testStorage, testCollection, "user")
+	require.Nil(t, err)
+	assert.Equal(t, map[string][]byte{"name": []byte("john")}, fields)
+
+	// hash w/o fields is deleted
+	_, err = HashDelete(testStorage, testCollection, "user", []string{"name"})
+	require.Nil(t, err)
+	assert.Equal(t, int64(0), testStorage.Stats().Memory)
+
+	_, err = ListPush(testStorage, testCollection, "list", true, [][]byte{[]byte("1")}, object.RequestSettings{})
+	require.Nil(t, err)
+	_, err = HashSet(testStorage, testCollection, "list", map[string][]byte{"a": nil}, object.RequestSettings{})
+	assert.Equal(t, errors.ErrWrongType, err)
+}
+
+func TestHashIncrement_Concurrent(t *testing.T) {
+	testStorage := New(testConfig)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(field string) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				_, err := HashIncrement(testStorage, testCollection, "counters", field, 1, object.RequestSettings{Timeless: true})
+				assert.Nil(t, err)
+			}
+		}(strconv.Itoa(g % 2))
+	}
+	wg.Wait()
+
+	// no lost updates of fields
+	fields, err := HashGetAll(testStorage, testCollection, "counters")
+	require.Nil(t, err)
+	assert.Equal(t, map[string][]byte{"0": []byte("400"), "1": []byte("400")}, fields)
+}
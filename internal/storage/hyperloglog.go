@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+// HyperLogLogAdd adds elements to hyperloglog and returns whether estimated count could be changed,
+// missing hyperloglog is created w expiration settings
+func HyperLogLogAdd(s Storage, collectionName, objectKey string, elements [][]byte, expiration object.RequestSettings) (bool, error) {
+	if len(elements) == 0 {
+		return false, errors.ErrEmptyField("elements")
+	}
+
+	var changed bool
+	_, err := updateValue(s, collectionName, objectKey, expiration, func(hll object.HyperLogLog) (object.HyperLogLog, error) {
+		hll, changed = hll.Add(elements...)
+		if !changed {
+			return hll, SkipUpdate
+		}
+		return hll, nil
+	})
+	return changed, err
+}
+
+// HyperLogLogCount returns estimated count of unique elements of union of hyperloglogs read at one moment,
+// missing hyperloglog is empty
+func HyperLogLogCount(s Storage, refs []ObjectRef) (uint64, error) {
+	if len(refs) == 0 {
+		return 0, errors.ErrEmptyField("keys")
+	}
+
+	objects, err := viewObjects(s, refs)
+	if err != nil {
+		return 0, err
+	}
+
+	hlls, err := hyperLogLogs(objects)
+	if err != nil {
+		return 0, err
+	}
+	if len(hlls) == 1 {
+		return hlls[0].Count(), nil
+	}
+	return object.HyperLogLog{}.Merge(hlls...).Count(), nil
+}
+
+// HyperLogLogMerge atomically merges hyperloglogs of sources into target, missing target is created w expiration settings,
+// existing target keeps its expiration
+func HyperLogLogMerge(s Storage, target ObjectRef, sources []ObjectRef, expiration object.RequestSettings) error {
+	if len(sources) == 0 {
+		return errors.ErrEmptyField("keys")
+	}
+
+	collection, err := s.GetCollection(target.Collection)
+	if err != nil {
+		return err
+	}
+
+	// object is created before update, so collection settings aren't read under collection lock
+	created := newObject(s, collection, expiration)
+	_, err = updateWithObjects(s, target, sources, func(current object.Object, objects []object.Object) (object.Object, error) {
+		hlls, err := hyperLogLogs(append([]object.Object{current}, objects...))
+		if err != nil {
+			return nil, err
+		}
+
+		merged := hlls[0].Merge(hlls[1:]...)
+		switch {
+		case merged.Len() == 0:
+			return nil, nil
+		case current != nil:
+			return current.WithValue(merged), nil
+		default:
+			return created.WithValue(merged), nil
+		}
+	})
+	return err
+}
+
+// hyperLogLogs returns values of objects, nil object is empty hyperloglog
+func hyperLogLogs(objects []object.Object) ([]object.HyperLogLog, error) {
+	hlls := make([]object.HyperLogLog, len(objects))
+	for i, obj := range objects {
+		if obj == nil {
+			continue
+		}
+
+		hll, ok := obj.Value().(object.HyperLogLog)
+		if !ok {
+			return nil, errors.ErrWrongType
+		}
+		hlls[i] = hll
+	}
+	return hlls, nil
+}
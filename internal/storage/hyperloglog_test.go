@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+func TestHyperLogLog(t *testing.T) {
+	testStorage := New(testConfig)
+
+	changed, err := HyperLogLogAdd(testStorage, testCollection, "monday", visitors(0, 100), object.RequestSettings{Timeout: 100})
+	require.Nil(t, err)
+	assert.True(t, changed)
+
+	// add w/o changes isn't write
+	version := objectVersion(t, testStorage, "monday")
+	changed, err = HyperLogLogAdd(testStorage, testCollection, "monday", visitors(0, 10), object.RequestSettings{})
+	require.Nil(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, version, objectVersion(t, testStorage, "monday"))
+
+	_, err = HyperLogLogAdd(testStorage, testCollection, "tuesday", visitors(50, 150), object.RequestSettings{})
+	require.Nil(t, err)
+
+	count, err := HyperLogLogCount(testStorage, []ObjectRef{{Key: "monday"}})
+	require.Nil(t, err)
+	assert.InDelta(t, 100, count, 2)
+
+	// union of hyperloglogs, missing hyperloglog is empty
+	count, err = HyperLogLogCount(testStorage, []ObjectRef{{Key: "monday"}, {Key: "tuesday"}, {Key: "unknown"}})
+	require.Nil(t, err)
+	assert.InDelta(t, 150, count, 3)
+
+	target := ObjectRef{Key: "week"}
+	require.Nil(t, HyperLogLogMerge(testStorage, target, []ObjectRef{{Key: "monday"}}, object.RequestSettings{Timeless: true}))
+	require.Nil(t, HyperLogLogMerge(testStorage, target, []ObjectRef{{Key: "tuesday"}}, object.RequestSettings{}))
+	count, err = HyperLogLogCount(testStorage, []ObjectRef{target})
+	require.Nil(t, err)
+	assert.InDelta(t, 150, count, 3)
+
+	// existing target keeps its expiration
+	ttl, err := ObjectTTL(testStorage, testCollection, "week")
+	require.Nil(t, err)
+	assert.Equal(t, time.Duration(-1), ttl)
+
+	// merge of empty hyperloglogs doesn't create target
+	require.Nil(t, HyperLogLogMerge(testStorage, ObjectRef{Key: "empty"}, []ObjectRef{{Key: "unknown"}}, object.RequestSettings{}))
+	_, err = ObjectTTL(testStorage, testCollection, "empty")
+	assert.Equal(t, errors.ErrNoObject("empty"), err)
+
+	require.Nil(t, setObject(testStorage, testCollection, testKey, testRequestSettings))
+	_, err = HyperLogLogAdd(testStorage, testCollection, testKey, visitors(0, 1), object.RequestSettings{})
+	assert.Equal(t, errors.ErrWrongType, err)
+	_, err = HyperLogLogCount(testStorage, []ObjectRef{{Key: testKey}})
+	assert.Equal(t, errors.ErrWrongType, err)
+	assert.Equal(t, errors.ErrWrongType, HyperLogLogMerge(testStorage, ObjectRef{Key: testKey}, []ObjectRef{{Key: "monday"}}, object.RequestSettings{}))
+}
+
+func visitors(from, to int) [][]byte {
+	visitors := make([][]byte, 0, to-from)
+	for i := from; i < to; i++ {
+		visitors = append(visitors, []byte("visitor:"+strconv.Itoa(i)))
+	}
+	return visitors
+}
@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+// ListPush adds values to the head or the tail of list and returns length of list,
+// missing list is created w expiration settings
+func ListPush(s Storage, collectionName, objectKey string, head bool, values [][]byte, expiration object.RequestSettings) (int, error) {
+	if len(values) == 0 {
+		return 0, errors.ErrEmptyField("values")
+	}
+
+	obj, err := updateValue(s, collectionName, objectKey, expiration, func(list object.List) (object.List, error) {
+		return list.Push(head, values...), nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return obj.Value().Len(), nil
+}
+
+// ListPop removes count values from the head or the tail of list and returns them,
+// list w/o values is deleted
+func ListPop(s Storage, collectionName, objectKey string, head bool, count int) ([][]byte, error) {
+	if count < 0 {
+		return nil, errors.ErrNegativeField("count")
+	}
+
+	var popped [][]byte
+	_, err := updateValue(s, collectionName, objectKey, object.RequestSettings{}, func(list object.List) (object.List, error) {
+		if list.Len() == 0 || count == 0 {
+			return list, SkipUpdate
+		}
+
+		list, popped = list.Pop(head, count)
+		return list, nil
+	})
+	return popped, err
+}
+
+// ListRange returns values from start to stop inclusive, negative index is counted from the tail
+func ListRange(s Storage, collectionName, objectKey string, start, stop int) ([][]byte, error) {
+	list, err := viewValue[object.List](s, collectionName, objectKey)
+	if err != nil {
+		return nil, err
+	}
+	return list.Range(start, stop), nil
+}
+
+// ListTrim keeps only values from start to stop inclusive
+func ListTrim(s Storage, collectionName, objectKey string, start, stop int) error {
+	_, err := updateValue(s, collectionName, objectKey, object.RequestSettings{}, func(list object.List) (object.List, error) {
+		if trimmed := list.Trim(start, stop); trimmed.Len() != list.Len() {
+			return trimmed, nil
+		}
+		return list, SkipUpdate
+	})
+	return err
+}
+
+func ListLen(s Storage, collectionName, objectKey string) (int, error) {
+	list, err := viewValue[object.List](s, collectionName, objectKey)
+	return list.Len(), err
+}
+
+// ListIndex returns value by index, nil if index is out of range
+func ListIndex(s Storage, collectionName, objectKey string, index int) ([]byte, error) {
+	list, err := viewValue[object.List](s, collectionName, objectKey)
+	if err != nil {
+		return nil, err
+	}
+
+	value, _ := list.Index(index)
+	return value, nil
+}
+
+// ListRemove removes count values equal to value and returns count of removed values,
+// positive count removes from the head, negative from the tail, 0 removes all
+func ListRemove(s Storage, collectionName, objectKey string, count int, value []byte) (int, error) {
+	var removed int
+	_, err := updateValue(s, collectionName, objectKey, object.RequestSettings{}, func(list object.List) (object.List, error) {
+		list, removed = list.Remove(count, value)
+		if removed == 0 {
+			return list, SkipUpdate
+		}
+		return list, nil
+	})
+	return removed, err
+}
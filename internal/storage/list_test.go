@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+func TestList(t *testing.T) {
+	testStorage := New(testConfig)
+	expiration := object.RequestSettings{Timeout: 100}
+
+	length, err := ListPush(testStorage, testCollection, "list", false, [][]byte{[]byte("b"), []byte("c")}, expiration)
+	require.Nil(t, err)
+	assert.Equal(t, 2, length)
+	length, err = ListPush(testStorage, testCollection, "list", true, [][]byte{[]byte("a")}, object.RequestSettings{})
+	require.Nil(t, err)
+	assert.Equal(t, 3, length)
+
+	values, err := ListRange(testStorage, testCollection, "list", 0, -1)
+	require.Nil(t, err)
+	assert.Equal(t, [][]byte{[]byte("a"), []byte("b"), []byte("c")}, values)
+
+	value, err := ListIndex(testStorage, testCollection, "list", 1)
+	require.Nil(t, err)
+	assert.Equal(t, []byte("b"), value)
+
+	// list keeps expiration of creation
+	ttl, err := ObjectTTL(testStorage, testCollection, "list")
+	require.Nil(t, err)
+	assert.Greater(t, ttl.Seconds(), float64(90))
+
+	popped, err := ListPop(testStorage, testCollection, "list", false, 1)
+	require.Nil(t, err)
+	assert.Equal(t, [][]byte{[]byte("c")}, popped)
+
+	require.Nil(t, ListTrim(testStorage, testCollection, "list", 1, 1))
+	length, err = ListLen(testStorage, testCollection, "list")
+	require.Nil(t, err)
+	assert.Equal(t, 1, length)
+
+	removed, err := ListRemove(testStorage, testCollection, "list", 0, []byte("b"))
+	require.Nil(t, err)
+	assert.Equal(t, 1, removed)
+
+	// empty list is deleted
+	collection, err := testStorage.GetCollection(testCollection)
+	require.Nil(t, err)
+	_, err = collection.Get("list")
+	assert.Equal(t, errors.ErrNoObject("list"), err)
+	assert.Equal(t, int64(0), testStorage.Stats().Memory)
+
+	popped, err = ListPop(testStorage, testCollection, "list", true, 1)
+	require.Nil(t, err)
+	assert.Empty(t, popped)
+}
+
+func TestList_WrongType(t *testing.T) {
+	testStorage := New(testConfig)
+	require.Nil(t, setObject(testStorage, testCollection, testKey, testRequestSettings))
+
+	_, err := ListPush(testStorage, testCollection, testKey, true, [][]byte{[]byte("a")}, object.RequestSettings{})
+	assert.Equal(t, errors.ErrWrongType, err)
+	_, err = ListRange(testStorage, testCollection, testKey, 0, -1)
+	assert.Equal(t, errors.ErrWrongType, err)
+
+	_, err = ListPush(testStorage, testCollection, "list", true, [][]byte{[]byte("1")}, object.RequestSettings{})
+	require.Nil(t, err)
+	_, err = GetObject(testStorage, testCollection, "list")
+	assert.Equal(t, errors.ErrWrongType, err)
+	_, err = IncrementObject(testStorage, testCollection, "list", 1, object.RequestSettings{})
+	assert.Equal(t, errors.ErrWrongType, err)
+}
+
+func TestList_Concurrent(t *testing.T) {
+	testStorage := New(testConfig)
+	const pushes = 100
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < pushes; i++ {
+				_, err := ListPush(testStorage, testCollection, "queue", false, [][]byte{[]byte(strconv.Itoa(i))}, object.RequestSettings{Timeless: true})
+				assert.Nil(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// every pushed value is popped once
+	var popped int
+	for {
+		values, err := ListPop(testStorage, testCollection, "queue", true, 7)
+		require.Nil(t, err)
+		if len(values) == 0 {
+			break
+		}
+		popped += len(values)
+	}
+	assert.Equal(t, 8*pushes, popped)
+}
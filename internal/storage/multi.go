@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"sort"
+
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+// ObjectRef is key of object in collection, empty collection is default one
+type ObjectRef struct {
+	Collection string `json:"collection"`
+	Key        string `json:"key"`
+}
+
+// lockShards locks shards in order of their ids, so concurrent callers don't deadlock,
+// every shard is locked once, returned func unlocks them
+func lockShards(shards []collection, write bool) (unlock func()) {
+	ordered := make([]collection, 0, len(shards))
+	locked := make(map[uint64]bool, len(shards))
+	for _, shard := range shards {
+		if !locked[shard.id] {
+			locked[shard.id] = true
+			ordered = append(ordered, shard)
+		}
+	}
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].id < ordered[j].id })
+
+	for _, shard := range ordered {
+		if write {
+			shard.mu.Lock()
+		} else {
+			shard.mu.RLock()
+		}
+	}
+
+	return func() {
+		for i := len(ordered) - 1; i >= 0; i-- {
+			if write {
+				ordered[i].mu.Unlock()
+			} else {
+				ordered[i].mu.RUnlock()
+			}
+		}
+	}
+}
+
+// refShards returns shards which hold objects of refs
+func refShards(s Storage, refs []ObjectRef) ([]collection, error) {
+	shards := make([]collection, len(refs))
+	for i, ref := range refs {
+		collection, err := s.GetCollection(ref.Collection)
+		if err != nil {
+			return nil, err
+		}
+		shards[i] = collection.shardOf(ref.Key)
+	}
+	return shards, nil
+}
+
+// viewObjects returns objects of refs read at one moment under locks of all their shards,
+// nil for missing or expired object
+func viewObjects(s Storage, refs []ObjectRef) ([]object.Object, error) {
+	shards, err := refShards(s, refs)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock := lockShards(shards, false)
+	defer unlock()
+
+	objects := make([]object.Object, len(refs))
+	for i, ref := range refs {
+		objects[i] = shards[i].peek(ref.Key)
+	}
+	return objects, nil
+}
+
+// updateWithObjects atomically updates object of target by update func called w objects of sources,
+// objects are read and written under locks of all their shards, nil for missing or expired source,
+// evictions for target are done only in its shard
+func updateWithObjects(s Storage, target ObjectRef, sources []ObjectRef, update func(current object.Object, sources []object.Object) (object.Object, error)) (object.Object, error) {
+	shards, err := refShards(s, append([]ObjectRef{target}, sources...))
+	if err != nil {
+		return nil, err
+	}
+
+	unlock := lockShards(shards, true)
+	defer unlock()
+
+	objects := make([]object.Object, len(sources))
+	for i, ref := range sources {
+		objects[i] = shards[i+1].peek(ref.Key)
+	}
+	return shards[0].update(target.Key, func(current object.Object) (object.Object, error) {
+		return update(current, objects)
+	})
+}
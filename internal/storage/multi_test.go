@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+func TestLockShards(t *testing.T) {
+	c := NewShardedCollection(4).(shardedCollection)
+
+	// the same shard is locked once
+	unlock := lockShards([]collection{c.shards[1], c.shards[0], c.shards[1]}, true)
+	unlock()
+	assert.True(t, c.shards[0].mu.TryLock())
+	assert.True(t, c.shards[1].mu.TryLock())
+}
+
+func TestViewObjects_Concurrent(t *testing.T) {
+	storageConfig := testConfig
+	storageConfig.ShardsCount = 4
+	testStorage := New(storageConfig)
+	refs := make([]ObjectRef, 16)
+	for i := range refs {
+		refs[i] = ObjectRef{Key: strconv.Itoa(i)}
+	}
+
+	// readers lock the same shards in different order of keys while writers change them
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(2)
+		go func(reversed bool) {
+			defer wg.Done()
+			ordered := make([]ObjectRef, len(refs))
+			for i := range refs {
+				ordered[i] = refs[i]
+				if reversed {
+					ordered[i] = refs[len(refs)-1-i]
+				}
+			}
+			for i := 0; i < 100; i++ {
+				_, err := viewObjects(testStorage, ordered)
+				assert.Nil(t, err)
+			}
+		}(g%2 == 0)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				_, err := SetAdd(testStorage, testCollection, refs[i%len(refs)].Key, []string{strconv.Itoa(i)}, object.RequestSettings{})
+				assert.Nil(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,69 @@
+package object
+
+import (
+	"math/rand"
+	"sync/atomic"
+	"time"
+)
+
+// Redis-like logarithmic frequency counter settings
+const (
+	// frequency of new object, so it won't be evicted at once
+	frequencyInit = 5
+	frequencyMax  = 255
+	// the bigger factor the more accesses are needed for incrementing counter
+	frequencyLogFactor = 10
+	// counter decrements by one every period w/o accesses
+	frequencyDecayPeriod = time.Minute
+)
+
+// access is metadata of object accesses for eviction policies,
+// it's shared by object copies and safe for concurrent use
+type access struct {
+	// unix nano time of last access, zero if object wasn't accessed
+	accessed  atomic.Int64
+	frequency atomic.Uint32
+}
+
+func newAccess() *access {
+	a := &access{}
+	a.frequency.Store(frequencyInit)
+	return a
+}
+
+func (a *access) touch() {
+	now := time.Now()
+	frequency := a.decayedFrequency(now)
+	if frequency < frequencyMax {
+		base := float64(frequency) - frequencyInit
+		if base < 0 {
+			base = 0
+		}
+		if rand.Float64() < 1/(base*frequencyLogFactor+1) {
+			frequency++
+		}
+	}
+
+	a.frequency.Store(uint32(frequency))
+	a.accessed.Store(now.UnixNano())
+}
+
+func (a *access) lastAccess() time.Time {
+	accessed := a.accessed.Load()
+	if accessed == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, accessed)
+}
+
+func (a *access) decayedFrequency(now time.Time) uint8 {
+	frequency := int64(a.frequency.Load())
+	if accessed := a.lastAccess(); !accessed.IsZero() {
+		frequency -= int64(now.Sub(accessed) / frequencyDecayPeriod)
+	}
+
+	if frequency < 0 {
+		return 0
+	}
+	return uint8(frequency)
+}
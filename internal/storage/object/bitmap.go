@@ -0,0 +1,363 @@
+package object
+
+import (
+	"encoding/binary"
+	"math/bits"
+
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+)
+
+// MaxBitOffset is max offset of bit in binary object, values are limited by 512MB like in Redis
+const MaxBitOffset = 1<<32 - 1
+
+// count of bytes and bits in page of bits
+const (
+	bitsPageSize = 4096
+	pageBits     = bitsPageSize * 8
+)
+
+// units of bit ranges
+const (
+	BitUnitByte BitUnit = "byte"
+	BitUnitBit  BitUnit = "bit"
+)
+
+// bitwise operations
+const (
+	BitOpAnd BitOperation = "and"
+	BitOpOr  BitOperation = "or"
+	BitOpXor BitOperation = "xor"
+	BitOpNot BitOperation = "not"
+)
+
+type (
+	// BitUnit is unit of indexes of bit range, empty unit is byte
+	BitUnit string
+
+	// BitOperation is bitwise operation on binary values
+	BitOperation string
+
+	// Bits is data of string object for bit operations, data is split into pages on the first setbit,
+	// pages are shared w previous versions and missing pages are zero bytes,
+	// so setbit copies only changed page and large offset doesn't allocate skipped bytes
+	Bits struct {
+		// data isn't split into pages yet
+		data   []byte
+		pages  treap[int, []byte]
+		length int
+	}
+)
+
+func NewBits(data []byte) Bits {
+	return Bits{data: data, length: len(data)}
+}
+
+// Len returns count of bytes
+func (b Bits) Len() int {
+	return b.length
+}
+
+// Bytes returns data, pages are joined into new slice, so it's used only for raw value of object
+func (b Bits) Bytes() []byte {
+	if b.pages.less == nil {
+		return b.data
+	}
+
+	data := make([]byte, b.length)
+	b.ascendPages(0, func(index int, page []byte) bool {
+		copy(data[index*bitsPageSize:], page)
+		return true
+	})
+	return data
+}
+
+// GetBit returns bit at offset, bit out of data is 0
+func (b Bits) GetBit(offset uint64) bool {
+	return GetBit(b.page(int(offset/pageBits)), offset%pageBits)
+}
+
+// SetBit returns bits w bit at offset and previous bit, bits are extended by zero bytes to fit offset
+func (b Bits) SetBit(offset uint64, bit bool) (Bits, bool, error) {
+	if offset > MaxBitOffset {
+		return b, false, errors.ErrBitOffsetOutOfRange
+	}
+
+	if b.pages.less == nil {
+		// pages share array of data, it's never changed
+		pages := newTreap[int, []byte](lessInt)
+		b.ascendPages(0, func(index int, page []byte) bool {
+			pages = pages.set(index, page)
+			return true
+		})
+		b.data, b.pages = nil, pages
+	}
+
+	index := int(offset / pageBits)
+	page, previous, _ := SetBit(b.page(index), offset%pageBits, bit)
+	b.pages = b.pages.set(index, page)
+	b.length = max(b.length, int(offset/8)+1)
+	return b, previous, nil
+}
+
+// BitCount returns count of set bits in range from start to end inclusive, negative index is counted from the end,
+// only existing pages in range are read
+func (b Bits) BitCount(start, end int, unit BitUnit) int {
+	from, to := bitBounds(b.length, start, end, unit)
+	count := 0
+	b.ascendPages(from/pageBits, func(index int, page []byte) bool {
+		offset := index * pageBits
+		if offset >= to {
+			return false
+		}
+		count += countBits(page, max(from-offset, 0), min(to-offset, len(page)*8))
+		return true
+	})
+	return count
+}
+
+// BitPos returns position of the first bit equal to bit in range from start to end inclusive, -1 if there is no such bit,
+// negative index is counted from the end, only existing pages in range are read
+func (b Bits) BitPos(bit bool, start, end int, unit BitUnit) int {
+	from, to := bitBounds(b.length, start, end, unit)
+	position := -1
+	// bits from next to the next page aren't in pages, so they are zero
+	next := from
+	b.ascendPages(from/pageBits, func(index int, page []byte) bool {
+		offset := index * pageBits
+		if !bit && next < min(offset, to) {
+			position = next
+			return false
+		}
+		if offset >= to {
+			return false
+		}
+
+		if found := findBit(page, bit, max(from-offset, 0), min(to-offset, len(page)*8)); found != -1 {
+			position = offset + found
+			return false
+		}
+		next = max(next, offset+len(page)*8)
+		return true
+	})
+
+	if position == -1 && !bit && next < to {
+		return next
+	}
+	return position
+}
+
+// page returns page by index, missing page is nil, page could be shorter than bitsPageSize, missing bytes are zero
+func (b Bits) page(index int) []byte {
+	if b.pages.less != nil {
+		page, _ := b.pages.get(index)
+		return page
+	}
+
+	from := index * bitsPageSize
+	if from >= len(b.data) {
+		return nil
+	}
+	return b.data[from:min(from+bitsPageSize, len(b.data))]
+}
+
+// ascendPages calls fn for existing pages from page w index first while fn returns true
+func (b Bits) ascendPages(first int, fn func(index int, page []byte) bool) {
+	if b.pages.less != nil {
+		b.pages.ascend(b.pages.rank(first), fn)
+		return
+	}
+
+	for from := first * bitsPageSize; from < len(b.data); from += bitsPageSize {
+		if !fn(from/bitsPageSize, b.data[from:min(from+bitsPageSize, len(b.data))]) {
+			return
+		}
+	}
+}
+
+// bits of binary data are numbered from the most significant bit of the first byte,
+// binary data isn't changed by bit functions, changed data is copy
+
+// SetBit returns copy of data w bit at offset and previous bit, data is extended by zero bytes to fit offset
+func SetBit(data []byte, offset uint64, bit bool) ([]byte, bool, error) {
+	if offset > MaxBitOffset {
+		return nil, false, errors.ErrBitOffsetOutOfRange
+	}
+
+	index := int(offset / 8)
+	updated := make([]byte, max(len(data), index+1))
+	copy(updated, data)
+
+	previous := GetBit(data, offset)
+	mask := byte(0x80) >> (offset % 8)
+	if bit {
+		updated[index] |= mask
+	} else {
+		updated[index] &^= mask
+	}
+	return updated, previous, nil
+}
+
+// GetBit returns bit at offset, bit out of data is 0
+func GetBit(data []byte, offset uint64) bool {
+	if offset >= uint64(len(data))*8 {
+		return false
+	}
+	return data[offset/8]&(byte(0x80)>>(offset%8)) != 0
+}
+
+// BitOp returns result of operation on sources, shorter sources are padded w zero bytes,
+// not operation has the only source, result is built page by page, so pages which are zero in all sources
+// are read and allocated only for not operation
+func BitOp(operation BitOperation, sources ...Bits) (Bits, error) {
+	switch {
+	case len(sources) == 0:
+		return Bits{}, errors.ErrEmptyField("keys")
+	case operation == BitOpNot && len(sources) != 1:
+		return Bits{}, errors.ErrBitOpNotSingleKey
+	}
+	if err := operation.Validate(); err != nil {
+		return Bits{}, err
+	}
+
+	// indexes of pages which could be non-zero in result
+	indexes := make(map[int]struct{})
+	length := 0
+	for _, source := range sources {
+		length = max(length, source.length)
+		source.ascendPages(0, func(index int, _ []byte) bool {
+			indexes[index] = struct{}{}
+			return true
+		})
+	}
+	if operation == BitOpNot {
+		// missing pages of source are ones in result
+		for index := 0; index*bitsPageSize < length; index++ {
+			indexes[index] = struct{}{}
+		}
+	}
+
+	result := Bits{pages: newTreap[int, []byte](lessInt), length: length}
+	pages := make([][]byte, len(sources))
+	for index := range indexes {
+		missing := false
+		for i, source := range sources {
+			pages[i] = source.page(index)
+			missing = missing || pages[i] == nil
+		}
+		if operation == BitOpAnd && missing {
+			continue
+		}
+
+		page := make([]byte, min(bitsPageSize, length-index*bitsPageSize))
+		bitOpPage(operation, page, pages)
+		result.pages = result.pages.set(index, page)
+	}
+	return result, nil
+}
+
+// Validate checks that operation is known
+func (o BitOperation) Validate() error {
+	switch o {
+	case BitOpAnd, BitOpOr, BitOpXor, BitOpNot:
+		return nil
+	default:
+		return errors.ErrUnknownValue("operation", string(o))
+	}
+}
+
+// Validate checks that unit is known
+func (u BitUnit) Validate() error {
+	switch u {
+	case "", BitUnitByte, BitUnitBit:
+		return nil
+	default:
+		return errors.ErrUnknownValue("unit", string(u))
+	}
+}
+
+// bitBounds converts inclusive indexes of unit to bounds of bits of data w length bytes
+func bitBounds(length, start, end int, unit BitUnit) (int, int) {
+	if unit == BitUnitBit {
+		return listBounds(start, end, length*8)
+	}
+
+	from, to := listBounds(start, end, length)
+	return from * 8, to * 8
+}
+
+// countBits returns count of set bits of data from bit from to bit to exclusive
+func countBits(data []byte, from, to int) int {
+	count := 0
+	for from < to {
+		switch {
+		case from%8 == 0 && to-from >= 64:
+			count += bits.OnesCount64(binary.BigEndian.Uint64(data[from/8:]))
+			from += 64
+		case from%8 == 0 && to-from >= 8:
+			count += bits.OnesCount8(data[from/8])
+			from += 8
+		default:
+			if GetBit(data, uint64(from)) {
+				count++
+			}
+			from++
+		}
+	}
+	return count
+}
+
+// findBit returns position of the first bit equal to bit of data from bit from to bit to exclusive or -1
+func findBit(data []byte, bit bool, from, to int) int {
+	// bytes w/o searched bit
+	skipped := byte(0)
+	if !bit {
+		skipped = 0xFF
+	}
+
+	for from < to {
+		if from%8 == 0 && to-from >= 8 && data[from/8] == skipped {
+			from += 8
+			continue
+		}
+		if GetBit(data, uint64(from)) == bit {
+			return from
+		}
+		from++
+	}
+	return -1
+}
+
+// bitOpPage writes result of operation on pages of sources to page, shorter pages are padded w zero bytes
+func bitOpPage(operation BitOperation, page []byte, sources [][]byte) {
+	switch operation {
+	case BitOpNot:
+		for i := range page {
+			if i < len(sources[0]) {
+				page[i] = ^sources[0][i]
+			} else {
+				page[i] = 0xFF
+			}
+		}
+	case BitOpAnd:
+		copy(page, sources[0])
+		for _, source := range sources[1:] {
+			for i := range page {
+				if i < len(source) {
+					page[i] &= source[i]
+				} else {
+					page[i] = 0
+				}
+			}
+		}
+	case BitOpOr, BitOpXor:
+		for _, source := range sources {
+			for i, b := range source {
+				if operation == BitOpOr {
+					page[i] |= b
+				} else {
+					page[i] ^= b
+				}
+			}
+		}
+	}
+}
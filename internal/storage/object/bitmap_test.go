@@ -0,0 +1,207 @@
+package object
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+)
+
+func TestSetBit(t *testing.T) {
+	data := []byte{0x00}
+
+	updated, previous, err := SetBit(data, 1, true)
+	require.Nil(t, err)
+	assert.False(t, previous)
+	assert.Equal(t, []byte{0x40}, updated)
+
+	// data is extended to fit offset
+	updated, previous, err = SetBit(updated, 15, true)
+	require.Nil(t, err)
+	assert.False(t, previous)
+	assert.Equal(t, []byte{0x40, 0x01}, updated)
+
+	updated, previous, err = SetBit(updated, 1, false)
+	require.Nil(t, err)
+	assert.True(t, previous)
+	assert.Equal(t, []byte{0x00, 0x01}, updated)
+
+	// source data isn't changed
+	assert.Equal(t, []byte{0x00}, data)
+
+	_, _, err = SetBit(data, MaxBitOffset+1, true)
+	assert.Equal(t, errors.ErrBitOffsetOutOfRange, err)
+
+	assert.True(t, GetBit(updated, 15))
+	assert.False(t, GetBit(updated, 14))
+	assert.False(t, GetBit(updated, 100))
+}
+
+func TestBits(t *testing.T) {
+	data := []byte{0x00, 0xFF}
+	bits := NewBits(data)
+
+	updated, previous, err := bits.SetBit(1, true)
+	require.Nil(t, err)
+	assert.False(t, previous)
+	assert.Equal(t, []byte{0x40, 0xFF}, updated.Bytes())
+
+	// bits are extended by pages to fit offset, skipped pages are zero
+	offset := uint64(3*bitsPageSize*8 + 7)
+	extended, previous, err := updated.SetBit(offset, true)
+	require.Nil(t, err)
+	assert.False(t, previous)
+	assert.Equal(t, 3*bitsPageSize+1, extended.Len())
+	assert.Equal(t, 2, extended.pages.len())
+	assert.True(t, extended.GetBit(offset))
+	assert.True(t, extended.GetBit(1))
+	assert.False(t, extended.GetBit(bitsPageSize*8))
+
+	expected := make([]byte, 3*bitsPageSize+1)
+	expected[0], expected[1], expected[3*bitsPageSize] = 0x40, 0xFF, 0x01
+	assert.Equal(t, expected, extended.Bytes())
+
+	cleared, previous, err := extended.SetBit(8, false)
+	require.Nil(t, err)
+	assert.True(t, previous)
+	assert.False(t, cleared.GetBit(8))
+
+	// source bits and data aren't changed
+	assert.True(t, extended.GetBit(8))
+	assert.Equal(t, []byte{0x40, 0xFF}, updated.Bytes())
+	assert.Equal(t, []byte{0x00, 0xFF}, data)
+
+	_, _, err = bits.SetBit(MaxBitOffset+1, true)
+	assert.Equal(t, errors.ErrBitOffsetOutOfRange, err)
+}
+
+func TestBits_Sparse(t *testing.T) {
+	sparse, _, err := NewBits([]byte{0x80}).SetBit(MaxBitOffset, true)
+	require.Nil(t, err)
+	assert.Equal(t, MaxBitOffset/8+1, sparse.Len())
+
+	// reads and bitop don't allocate missing pages
+	assert.Equal(t, 2, sparse.BitCount(0, -1, ""))
+	assert.Equal(t, 1, sparse.BitCount(1, -1, ""))
+	assert.Equal(t, MaxBitOffset, sparse.BitPos(true, 1, -1, ""))
+	assert.Equal(t, 1, sparse.BitPos(false, 0, -1, ""))
+	assert.Equal(t, pageBits, sparse.BitPos(false, pageBits, -1, BitUnitBit))
+	assert.Equal(t, -1, sparse.BitPos(false, -1, -1, BitUnitBit))
+
+	other, _, err := NewBits(nil).SetBit(MaxBitOffset, true)
+	require.Nil(t, err)
+	and, err := BitOp(BitOpAnd, sparse, other)
+	require.Nil(t, err)
+	assert.Equal(t, sparse.Len(), and.Len())
+	assert.Equal(t, 1, and.pages.len())
+	assert.Equal(t, 1, and.BitCount(0, -1, ""))
+
+	or, err := BitOp(BitOpOr, sparse, NewBits([]byte{0x01}))
+	require.Nil(t, err)
+	assert.Equal(t, 2, or.pages.len())
+	assert.Equal(t, 3, or.BitCount(0, -1, ""))
+}
+
+// pagedBits returns bits of data split into pages
+func pagedBits(data []byte) Bits {
+	bits := NewBits(data)
+	if len(data) == 0 {
+		return bits
+	}
+	bits, _, _ = bits.SetBit(0, bits.GetBit(0))
+	return bits
+}
+
+func TestBitCount(t *testing.T) {
+	// "foobar" like in Redis docs
+	data := []byte("foobar")
+	long := append(make([]byte, 16), 0xFF, 0x0F)
+
+	tests := []struct {
+		name       string
+		data       []byte
+		start, end int
+		unit       BitUnit
+		want       int
+	}{
+		{name: "all", data: data, start: 0, end: -1, want: 26},
+		{name: "bytes", data: data, start: 1, end: 1, want: 6},
+		{name: "bits", data: data, start: 5, end: 30, unit: BitUnitBit, want: 17},
+		{name: "negative", data: data, start: -2, end: -1, want: 7},
+		{name: "out of range", data: data, start: 10, end: 20},
+		{name: "words", data: long, start: 0, end: -1, want: 12},
+		{name: "empty", start: 0, end: -1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, NewBits(test.data).BitCount(test.start, test.end, test.unit))
+			assert.Equal(t, test.want, pagedBits(test.data).BitCount(test.start, test.end, test.unit))
+		})
+	}
+}
+
+func TestBitPos(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       []byte
+		bit        bool
+		start, end int
+		unit       BitUnit
+		want       int
+	}{
+		{name: "clear bit", data: []byte{0xFF, 0xF0, 0x00}, start: 0, end: -1, want: 12},
+		{name: "set bit", data: []byte{0x00, 0xFF, 0xF0}, bit: true, start: 0, end: -1, want: 8},
+		{name: "set bit from byte", data: []byte{0x00, 0xFF, 0xF0}, bit: true, start: 2, end: -1, want: 16},
+		{name: "set bit from bit", data: []byte{0x00, 0xFF, 0xF0}, bit: true, start: 7, end: 15, unit: BitUnitBit, want: 8},
+		{name: "no set bit", data: []byte{0x00, 0x00}, bit: true, start: 0, end: -1, want: -1},
+		{name: "no clear bit", data: []byte{0xFF}, start: 0, end: -1, want: -1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, NewBits(test.data).BitPos(test.bit, test.start, test.end, test.unit))
+			assert.Equal(t, test.want, pagedBits(test.data).BitPos(test.bit, test.start, test.end, test.unit))
+		})
+	}
+}
+
+func TestBitOp(t *testing.T) {
+	first, second := []byte{0xF0, 0xFF}, []byte{0x3C}
+
+	tests := []struct {
+		name      string
+		operation BitOperation
+		sources   [][]byte
+		want      []byte
+		wantErr   error
+	}{
+		{name: "and", operation: BitOpAnd, sources: [][]byte{first, second}, want: []byte{0x30, 0x00}},
+		{name: "or", operation: BitOpOr, sources: [][]byte{first, second}, want: []byte{0xFC, 0xFF}},
+		{name: "xor", operation: BitOpXor, sources: [][]byte{first, second}, want: []byte{0xCC, 0xFF}},
+		{name: "not", operation: BitOpNot, sources: [][]byte{first}, want: []byte{0x0F, 0x00}},
+		{name: "and w empty", operation: BitOpAnd, sources: [][]byte{first, nil}, want: []byte{0x00, 0x00}},
+		{name: "not w two sources", operation: BitOpNot, sources: [][]byte{first, second}, wantErr: errors.ErrBitOpNotSingleKey},
+		{name: "unknown", operation: "nand", sources: [][]byte{first}, wantErr: errors.ErrUnknownValue("operation", "nand")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sources := make([]Bits, len(test.sources))
+			for i, source := range test.sources {
+				sources[i] = NewBits(source)
+			}
+
+			result, err := BitOp(test.operation, sources...)
+			assert.Equal(t, test.wantErr, err)
+			if test.wantErr == nil {
+				assert.Equal(t, test.want, result.Bytes())
+			}
+		})
+	}
+
+	// sources aren't changed
+	assert.Equal(t, []byte{0xF0, 0xFF}, first)
+}
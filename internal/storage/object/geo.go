@@ -0,0 +1,222 @@
+package object
+
+import (
+	"math"
+	"sort"
+
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+)
+
+const (
+	// bits of every coordinate in geohash, score of geo member is 52 bits geohash
+	geoStep = 26
+	// limits of coordinates like in Redis
+	GeoLongitudeMax = 180
+	GeoLatitudeMax  = 85.05112878
+	// earth radius in meters for haversine distance like in Redis
+	earthRadius = 6372797.560856
+	// meters of one degree of meridian
+	metersPerDegree = earthRadius * math.Pi / 180
+)
+
+// units of distances
+const (
+	GeoUnitMeters     GeoUnit = "m"
+	GeoUnitKilometers GeoUnit = "km"
+	GeoUnitMiles      GeoUnit = "mi"
+	GeoUnitFeet       GeoUnit = "ft"
+)
+
+type (
+	// GeoPoint is location of geo member
+	GeoPoint struct {
+		Longitude float64 `json:"longitude"`
+		Latitude  float64 `json:"latitude"`
+	}
+
+	// GeoQuery is area of geo search, circle w radius or box w width and height around center,
+	// all lengths are in meters
+	GeoQuery struct {
+		Center GeoPoint
+		Radius float64
+		Width  float64
+		Height float64
+	}
+
+	// GeoResult is member found by geo search w distance to center of area in meters
+	GeoResult struct {
+		Member   string
+		Point    GeoPoint
+		Distance float64
+	}
+
+	// GeoUnit is unit of distances, empty unit is meters
+	GeoUnit string
+)
+
+// geo members are members of sorted set w geohash scores, so member location is center of geohash cell
+
+// GeoScore returns geohash of point as score of sorted set member
+func GeoScore(point GeoPoint) (float64, error) {
+	if math.Abs(point.Longitude) > GeoLongitudeMax || math.Abs(point.Latitude) > GeoLatitudeMax {
+		return 0, errors.ErrInvalidCoordinates(point.Longitude, point.Latitude)
+	}
+
+	x, y := geoCell(point, geoStep)
+	return float64(interleave(x, y)), nil
+}
+
+// GeoPointOf returns point of geohash score
+func GeoPointOf(score float64) GeoPoint {
+	x, y := deinterleave(uint64(score))
+	cells := float64(uint64(1) << geoStep)
+	return GeoPoint{
+		Longitude: -GeoLongitudeMax + (float64(x)+0.5)*2*GeoLongitudeMax/cells,
+		Latitude:  -GeoLatitudeMax + (float64(y)+0.5)*2*GeoLatitudeMax/cells,
+	}
+}
+
+// GeoDistance returns haversine distance between points in meters
+func GeoDistance(a, b GeoPoint) float64 {
+	latA, latB := radians(a.Latitude), radians(b.Latitude)
+	sinLat := math.Sin((latB - latA) / 2)
+	sinLon := math.Sin(radians(b.Longitude-a.Longitude) / 2)
+	h := sinLat*sinLat + math.Cos(latA)*math.Cos(latB)*sinLon*sinLon
+	return 2 * earthRadius * math.Asin(math.Sqrt(h))
+}
+
+// GeoSearch returns geo members in area ordered by distance to center, the nearest first
+func (z ZSet) GeoSearch(query GeoQuery) []GeoResult {
+	var results []GeoResult
+	for _, cells := range query.cellRanges() {
+		for _, member := range z.RangeByScore(cells[0], cells[1], 0) {
+			point := GeoPointOf(member.Score)
+			distance, ok := query.contains(point)
+			if ok {
+				results = append(results, GeoResult{Member: member.Member, Point: point, Distance: distance})
+			}
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Distance != results[j].Distance {
+			return results[i].Distance < results[j].Distance
+		}
+		return results[i].Member < results[j].Member
+	})
+	return results
+}
+
+// contains returns distance to point and whether point is in area
+func (q GeoQuery) contains(point GeoPoint) (float64, bool) {
+	distance := GeoDistance(q.Center, point)
+	if q.Radius > 0 {
+		return distance, distance <= q.Radius
+	}
+
+	// distances along meridian and along parallel of point like in Redis
+	latDistance := math.Abs(point.Latitude-q.Center.Latitude) * metersPerDegree
+	lonDistance := GeoDistance(GeoPoint{Longitude: q.Center.Longitude, Latitude: point.Latitude}, point)
+	return distance, latDistance <= q.Height/2 && lonDistance <= q.Width/2
+}
+
+// cellRanges returns score ranges of geohash cells which cover area:
+// cells are not smaller than area, so cell of center and its neighbours contain whole area
+func (q GeoQuery) cellRanges() [][2]ScoreBound {
+	radius := q.Radius
+	if radius == 0 {
+		radius = math.Hypot(q.Width, q.Height) / 2
+	}
+
+	step := geoSearchStep(q.Center, radius)
+	if step == 0 {
+		return [][2]ScoreBound{{{Score: 0}, {Score: 1 << (2 * geoStep), Exclusive: true}}}
+	}
+
+	cells := uint64(1) << step
+	x, y := geoCell(q.Center, step)
+	shift := 2 * (geoStep - step)
+	seen := make(map[uint64]bool, 9)
+	ranges := make([][2]ScoreBound, 0, 9)
+	for dy := -1; dy <= 1; dy++ {
+		ny := int64(y) + int64(dy)
+		if ny < 0 || ny >= int64(cells) {
+			continue
+		}
+		for dx := -1; dx <= 1; dx++ {
+			// longitude wraps around antimeridian
+			nx := (int64(x) + int64(dx) + int64(cells)) % int64(cells)
+			hash := interleave(uint64(nx), uint64(ny))
+			if seen[hash] {
+				continue
+			}
+			seen[hash] = true
+			ranges = append(ranges, [2]ScoreBound{
+				{Score: float64(hash << shift)},
+				{Score: float64((hash + 1) << shift), Exclusive: true},
+			})
+		}
+	}
+	return ranges
+}
+
+// geoSearchStep returns the biggest step whose cells are not smaller than radius, 0 - area needs all cells
+func geoSearchStep(center GeoPoint, radius float64) int {
+	// cells are the narrowest at latitude of area the farthest from equator
+	latitude := math.Min(math.Abs(center.Latitude)+radius/metersPerDegree, 90)
+	for step := geoStep; step > 0; step-- {
+		cells := float64(uint64(1) << step)
+		height := 2 * GeoLatitudeMax / cells * metersPerDegree
+		width := 2 * GeoLongitudeMax / cells * metersPerDegree * math.Cos(radians(latitude))
+		if height >= radius && width >= radius {
+			return step
+		}
+	}
+	return 0
+}
+
+// Meters returns meters in one unit
+func (u GeoUnit) Meters() (float64, error) {
+	switch u {
+	case "", GeoUnitMeters:
+		return 1, nil
+	case GeoUnitKilometers:
+		return 1000, nil
+	case GeoUnitMiles:
+		return 1609.34, nil
+	case GeoUnitFeet:
+		return 0.3048, nil
+	default:
+		return 0, errors.ErrUnknownValue("unit", string(u))
+	}
+}
+
+// geoCell returns indexes of cell of point by longitude and latitude for step bits of coordinates
+func geoCell(point GeoPoint, step int) (uint64, uint64) {
+	cells := float64(uint64(1) << step)
+	x := (point.Longitude + GeoLongitudeMax) / (2 * GeoLongitudeMax) * cells
+	y := (point.Latitude + GeoLatitudeMax) / (2 * GeoLatitudeMax) * cells
+	return uint64(math.Min(x, cells-1)), uint64(math.Min(y, cells-1))
+}
+
+// interleave returns bits of x at odd positions and bits of y at even positions
+func interleave(x, y uint64) uint64 {
+	var hash uint64
+	for i := 0; i < geoStep; i++ {
+		hash |= (y>>i&1)<<(2*i) | (x>>i&1)<<(2*i+1)
+	}
+	return hash
+}
+
+func deinterleave(hash uint64) (uint64, uint64) {
+	var x, y uint64
+	for i := 0; i < geoStep; i++ {
+		y |= (hash >> (2 * i) & 1) << i
+		x |= (hash >> (2*i + 1) & 1) << i
+	}
+	return x, y
+}
+
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
@@ -0,0 +1,118 @@
+package object
+
+import (
+	"math/rand"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+)
+
+// locations from Redis docs
+var (
+	palermo = GeoPoint{Longitude: 13.361389, Latitude: 38.115556}
+	catania = GeoPoint{Longitude: 15.087269, Latitude: 37.502669}
+)
+
+func TestGeoScore(t *testing.T) {
+	for _, point := range []GeoPoint{palermo, catania, {Longitude: -180, Latitude: -GeoLatitudeMax}, {Longitude: 180, Latitude: GeoLatitudeMax}} {
+		score, err := GeoScore(point)
+		require.Nil(t, err)
+		// location of member is center of geohash cell
+		assert.Less(t, GeoDistance(point, GeoPointOf(score)), 1.0)
+	}
+
+	_, err := GeoScore(GeoPoint{Longitude: 10, Latitude: 86})
+	assert.Equal(t, errors.ErrInvalidCoordinates(10, 86), err)
+}
+
+func TestGeoDistance(t *testing.T) {
+	assert.InDelta(t, 166274.1516, GeoDistance(palermo, catania), 0.5)
+	assert.Equal(t, float64(0), GeoDistance(palermo, palermo))
+}
+
+func TestZSet_GeoSearch(t *testing.T) {
+	zset := geoSet(t, map[string]GeoPoint{"Palermo": palermo, "Catania": catania, "Edge": {Longitude: 17.24151, Latitude: 38.788135}})
+
+	tests := []struct {
+		name  string
+		query GeoQuery
+		want  []string
+	}{
+		{name: "radius", query: GeoQuery{Center: GeoPoint{Longitude: 15, Latitude: 37}, Radius: 200000}, want: []string{"Catania", "Palermo"}},
+		{name: "small radius", query: GeoQuery{Center: GeoPoint{Longitude: 15, Latitude: 37}, Radius: 100000}, want: []string{"Catania"}},
+		{name: "box", query: GeoQuery{Center: GeoPoint{Longitude: 15, Latitude: 37}, Width: 400000, Height: 400000}, want: []string{"Catania", "Palermo", "Edge"}},
+		{name: "nothing", query: GeoQuery{Center: GeoPoint{Longitude: -70, Latitude: 40}, Radius: 1000}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var members []string
+			for _, result := range zset.GeoSearch(test.query) {
+				members = append(members, result.Member)
+			}
+			assert.Equal(t, test.want, members)
+		})
+	}
+
+	results := zset.GeoSearch(GeoQuery{Center: GeoPoint{Longitude: 15, Latitude: 37}, Radius: 200000})
+	require.Len(t, results, 2)
+	assert.InDelta(t, 56441.3, results[0].Distance, 1)
+	assert.InDelta(t, 190442.4, results[1].Distance, 1)
+}
+
+// search by cells of geohash finds the same members as check of all members
+func TestZSet_GeoSearchRandom(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	locations := make(map[string]GeoPoint, 2000)
+	for i := 0; i < 2000; i++ {
+		locations[strconv.Itoa(i)] = GeoPoint{
+			Longitude: random.Float64()*360 - 180,
+			Latitude:  random.Float64()*2*GeoLatitudeMax - GeoLatitudeMax,
+		}
+	}
+	zset := geoSet(t, locations)
+
+	for i := 0; i < 200; i++ {
+		query := GeoQuery{Center: GeoPoint{
+			Longitude: random.Float64()*360 - 180,
+			Latitude:  random.Float64()*160 - 80,
+		}}
+		if i%2 == 0 {
+			query.Radius = random.Float64() * 3000000
+		} else {
+			query.Width, query.Height = random.Float64()*3000000, random.Float64()*3000000
+		}
+
+		var want []string
+		for _, member := range zset.Range(0, -1) {
+			if _, ok := query.contains(GeoPointOf(member.Score)); ok {
+				want = append(want, member.Member)
+			}
+		}
+
+		var found []string
+		for _, result := range zset.GeoSearch(query) {
+			found = append(found, result.Member)
+		}
+		sort.Strings(want)
+		sort.Strings(found)
+		require.Equal(t, want, found, "query %+v", query)
+	}
+}
+
+func geoSet(t *testing.T, locations map[string]GeoPoint) ZSet {
+	members := make([]ScoredMember, 0, len(locations))
+	for member, point := range locations {
+		score, err := GeoScore(point)
+		require.Nil(t, err)
+		members = append(members, ScoredMember{Member: member, Score: score})
+	}
+
+	zset, _, _ := ZSet{}.Add(members, ZAddOptions{})
+	return zset
+}
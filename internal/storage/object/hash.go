@@ -0,0 +1,73 @@
+package object
+
+import "encoding/json"
+
+// Hash is value of hash object, fields are kept in persistent treap,
+// so writes copy only changed paths of tree
+type Hash struct {
+	fields treap[string, []byte]
+	size   int
+}
+
+func (h Hash) Type() Type {
+	return TypeHash
+}
+
+func (h Hash) Len() int {
+	return h.fields.len()
+}
+
+// Size returns count of fields and values bytes
+func (h Hash) Size() int {
+	return h.size
+}
+
+func (h Hash) MarshalJSON() ([]byte, error) {
+	return json.Marshal(h.Fields())
+}
+
+// Set returns hash w values of fields and count of added fields
+func (h Hash) Set(values map[string][]byte) (Hash, int) {
+	if h.fields.less == nil {
+		h.fields = newTreap[string, []byte](lessString)
+	}
+
+	added := 0
+	for field, value := range values {
+		if old, ok := h.fields.get(field); ok {
+			h.size -= len(field) + len(old)
+		} else {
+			added++
+		}
+		h.fields = h.fields.set(field, value)
+		h.size += len(field) + len(value)
+	}
+	return h, added
+}
+
+func (h Hash) Get(field string) ([]byte, bool) {
+	return h.fields.get(field)
+}
+
+// Delete returns hash w/o fields and count of deleted fields
+func (h Hash) Delete(fields ...string) (Hash, int) {
+	deleted := 0
+	for _, field := range fields {
+		if value, ok := h.fields.get(field); ok {
+			h.fields, _ = h.fields.delete(field)
+			h.size -= len(field) + len(value)
+			deleted++
+		}
+	}
+	return h, deleted
+}
+
+// Fields returns copy of fields
+func (h Hash) Fields() map[string][]byte {
+	fields := make(map[string][]byte, h.fields.len())
+	h.fields.ascend(0, func(field string, value []byte) bool {
+		fields[field] = value
+		return true
+	})
+	return fields
+}
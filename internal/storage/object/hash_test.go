@@ -0,0 +1,31 @@
+package object
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHash(t *testing.T) {
+	hash, added := Hash{}.Set(map[string][]byte{"name": []byte("john"), "age": []byte("30")})
+	assert.Equal(t, 2, added)
+	assert.Equal(t, 2, hash.Len())
+	assert.Equal(t, len("name")+len("john")+len("age")+len("30"), hash.Size())
+
+	updated, added := hash.Set(map[string][]byte{"age": []byte("31"), "city": []byte("NY")})
+	assert.Equal(t, 1, added)
+	assert.Equal(t, len("name")+len("john")+len("age")+len("31")+len("city")+len("NY"), updated.Size())
+
+	// source hash isn't changed
+	value, ok := hash.Get("age")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("30"), value)
+	_, ok = hash.Get("city")
+	assert.False(t, ok)
+
+	deleted, count := updated.Delete("name", "unknown")
+	assert.Equal(t, 1, count)
+	assert.Equal(t, map[string][]byte{"age": []byte("31"), "city": []byte("NY")}, deleted.Fields())
+	assert.Equal(t, len("age")+len("31")+len("city")+len("NY"), deleted.Size())
+	assert.Equal(t, 3, updated.Len())
+}
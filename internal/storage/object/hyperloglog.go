@@ -0,0 +1,246 @@
+package object
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"math"
+	"math/bits"
+	"sort"
+)
+
+const (
+	// count of registers is 2^precision, standard error of count is 1.04/sqrt(registers) ~ 0.81%
+	hllPrecision    = 14
+	hllRegisters    = 1 << hllPrecision
+	hllRegisterBits = 6
+	// dense registers are packed by 6 bits, so dense encoding takes 12KB
+	hllDenseSize = hllRegisters * hllRegisterBits / 8
+	// max count of non-zero registers of sparse encoding, 3000 bytes like in Redis
+	hllSparseMax = 750
+	// size of sparse register, index and value
+	hllSparseSize = 4
+)
+
+// encodings of hyperloglog
+const (
+	HLLSparse = "sparse"
+	HLLDense  = "dense"
+)
+
+type (
+	// HyperLogLog is value of cardinality estimation object,
+	// small hyperloglog stores only non-zero registers sorted by index (sparse encoding)
+	// and becomes array of all registers (dense encoding) when it's bigger
+	HyperLogLog struct {
+		// index << 8 | value of non-zero registers for sparse encoding
+		sparse []uint32
+		// packed registers for dense encoding
+		dense []byte
+		// count of non-zero registers
+		registers int
+	}
+)
+
+func (h HyperLogLog) Type() Type {
+	return TypeHyperLogLog
+}
+
+// Len returns count of non-zero registers
+func (h HyperLogLog) Len() int {
+	return h.registers
+}
+
+func (h HyperLogLog) Size() int {
+	if h.dense != nil {
+		return hllDenseSize
+	}
+	return len(h.sparse) * hllSparseSize
+}
+
+func (h HyperLogLog) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Encoding string `json:"encoding"`
+		Count    uint64 `json:"count"`
+	}{
+		Encoding: h.Encoding(),
+		Count:    h.Count(),
+	})
+}
+
+func (h HyperLogLog) Encoding() string {
+	if h.dense != nil {
+		return HLLDense
+	}
+	return HLLSparse
+}
+
+// Add returns hyperloglog w elements and whether any register is changed
+func (h HyperLogLog) Add(elements ...[]byte) (HyperLogLog, bool) {
+	changed := false
+	for _, element := range elements {
+		index, rank := hllHash(element)
+		if h.register(index) >= rank {
+			continue
+		}
+
+		// registers are copied once for all elements
+		if !changed {
+			h, changed = h.copy(), true
+		}
+		h = h.set(index, rank)
+	}
+	return h, changed
+}
+
+// Merge returns hyperloglog of union of hyperloglog and others
+func (h HyperLogLog) Merge(others ...HyperLogLog) HyperLogLog {
+	h = h.copy()
+	for _, other := range others {
+		other.each(func(index int, value uint8) {
+			if h.register(index) < value {
+				h = h.set(index, value)
+			}
+		})
+	}
+	return h
+}
+
+// Count returns estimated count of unique elements
+func (h HyperLogLog) Count() uint64 {
+	zeros := hllRegisters - h.registers
+	sum := float64(zeros)
+	h.each(func(_ int, value uint8) {
+		sum += math.Ldexp(1, -int(value))
+	})
+
+	m := float64(hllRegisters)
+	estimate := 0.7213 / (1 + 1.079/m) * m * m / sum
+	// linear counting is more accurate for small cardinalities
+	if estimate <= 2.5*m && zeros != 0 {
+		estimate = m * math.Log(m/float64(zeros))
+	}
+	return uint64(estimate + 0.5)
+}
+
+func (h HyperLogLog) register(index int) uint8 {
+	if h.dense != nil {
+		return denseRegister(h.dense, index)
+	}
+
+	i, ok := h.sparseIndex(index)
+	if !ok {
+		return 0
+	}
+	return uint8(h.sparse[i])
+}
+
+// set register w/o copy, registers must be owned by hyperloglog
+func (h HyperLogLog) set(index int, value uint8) HyperLogLog {
+	if h.dense != nil {
+		if denseRegister(h.dense, index) == 0 {
+			h.registers++
+		}
+		setDenseRegister(h.dense, index, value)
+		return h
+	}
+
+	register := uint32(index)<<8 | uint32(value)
+	i, ok := h.sparseIndex(index)
+	if ok {
+		h.sparse[i] = register
+		return h
+	}
+
+	if len(h.sparse) == hllSparseMax {
+		return h.toDense().set(index, value)
+	}
+	h.sparse = append(h.sparse, 0)
+	copy(h.sparse[i+1:], h.sparse[i:])
+	h.sparse[i] = register
+	h.registers++
+	return h
+}
+
+// sparseIndex returns position of register in sparse registers or position for insert
+func (h HyperLogLog) sparseIndex(index int) (int, bool) {
+	i := sort.Search(len(h.sparse), func(i int) bool { return int(h.sparse[i]>>8) >= index })
+	return i, i < len(h.sparse) && int(h.sparse[i]>>8) == index
+}
+
+// each calls fn for non-zero registers
+func (h HyperLogLog) each(fn func(index int, value uint8)) {
+	if h.dense == nil {
+		for _, register := range h.sparse {
+			fn(int(register>>8), uint8(register))
+		}
+		return
+	}
+
+	for index := 0; index < hllRegisters; index++ {
+		if value := denseRegister(h.dense, index); value != 0 {
+			fn(index, value)
+		}
+	}
+}
+
+func (h HyperLogLog) toDense() HyperLogLog {
+	dense := make([]byte, hllDenseSize)
+	for _, register := range h.sparse {
+		setDenseRegister(dense, int(register>>8), uint8(register))
+	}
+	h.sparse, h.dense = nil, dense
+	return h
+}
+
+func (h HyperLogLog) copy() HyperLogLog {
+	if h.dense != nil {
+		h.dense = append([]byte(nil), h.dense...)
+		return h
+	}
+	h.sparse = append(make([]uint32, 0, len(h.sparse)+1), h.sparse...)
+	return h
+}
+
+func denseRegister(dense []byte, index int) uint8 {
+	position := index * hllRegisterBits
+	word := uint16(dense[position/8])
+	if position/8+1 < len(dense) {
+		word |= uint16(dense[position/8+1]) << 8
+	}
+	return uint8(word>>(position%8)) & (1<<hllRegisterBits - 1)
+}
+
+func setDenseRegister(dense []byte, index int, value uint8) {
+	position := index * hllRegisterBits
+	shift := position % 8
+	mask := uint16(1<<hllRegisterBits-1) << shift
+	word := uint16(dense[position/8])
+	if position/8+1 < len(dense) {
+		word |= uint16(dense[position/8+1]) << 8
+	}
+
+	word = word&^mask | uint16(value)<<shift
+	dense[position/8] = byte(word)
+	if position/8+1 < len(dense) {
+		dense[position/8+1] = byte(word >> 8)
+	}
+}
+
+// hllHash returns index of register and rank of element,
+// rank is position of the first set bit of hash w/o index bits
+func hllHash(element []byte) (int, uint8) {
+	hash := fnv.New64a()
+	hash.Write(element)
+	x := hash.Sum64()
+
+	// fnv hash is mixed, so all bits of hash are uniform
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+
+	index := int(x & (hllRegisters - 1))
+	rank := bits.TrailingZeros64(x>>hllPrecision|1<<(64-hllPrecision)) + 1
+	return index, uint8(rank)
+}
@@ -0,0 +1,85 @@
+package object
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHyperLogLog(t *testing.T) {
+	hll, changed := HyperLogLog{}.Add([]byte("a"), []byte("b"), []byte("c"), []byte("a"))
+	assert.True(t, changed)
+	assert.Equal(t, uint64(3), hll.Count())
+	assert.Equal(t, HLLSparse, hll.Encoding())
+	assert.Equal(t, 3*hllSparseSize, hll.Size())
+
+	same, changed := hll.Add([]byte("b"))
+	assert.False(t, changed)
+	assert.Equal(t, hll, same)
+
+	// sparse hyperloglog becomes dense when it's big
+	dense, _ := hll.Add(elements(0, 2000)...)
+	assert.Equal(t, HLLDense, dense.Encoding())
+	assert.Equal(t, hllDenseSize, dense.Size())
+	assert.Equal(t, 12288, dense.Size())
+
+	// source hyperloglog isn't changed
+	assert.Equal(t, uint64(3), hll.Count())
+
+	data, err := json.Marshal(hll)
+	require.Nil(t, err)
+	assert.JSONEq(t, `{"encoding":"sparse","count":3}`, string(data))
+}
+
+func TestHyperLogLog_Count(t *testing.T) {
+	tests := []int{10, 100, 1000, 10000, 100000}
+
+	for _, count := range tests {
+		t.Run(strconv.Itoa(count), func(t *testing.T) {
+			hll, _ := HyperLogLog{}.Add(elements(0, count)...)
+			assertEstimate(t, count, hll.Count())
+		})
+	}
+}
+
+func TestHyperLogLog_Merge(t *testing.T) {
+	first, _ := HyperLogLog{}.Add(elements(0, 30000)...)
+	second, _ := HyperLogLog{}.Add(elements(20000, 50000)...)
+	small, _ := HyperLogLog{}.Add(elements(49900, 50100)...)
+
+	merged := first.Merge(second, small)
+	assertEstimate(t, 50100, merged.Count())
+	assertEstimate(t, 30000, first.Count())
+
+	sparse := HyperLogLog{}.Merge(small)
+	assert.Equal(t, HLLSparse, sparse.Encoding())
+	assert.Equal(t, small.Count(), sparse.Count())
+}
+
+func TestDenseRegisters(t *testing.T) {
+	dense := make([]byte, hllDenseSize)
+	for index := 0; index < hllRegisters; index++ {
+		setDenseRegister(dense, index, uint8(index%64))
+	}
+	for index := 0; index < hllRegisters; index++ {
+		require.Equal(t, uint8(index%64), denseRegister(dense, index))
+	}
+}
+
+func elements(from, to int) [][]byte {
+	elements := make([][]byte, 0, to-from)
+	for i := from; i < to; i++ {
+		elements = append(elements, []byte("visitor:"+strconv.Itoa(i)))
+	}
+	return elements
+}
+
+// assertEstimate checks that estimate is within 3 standard errors
+func assertEstimate(t *testing.T, want int, estimate uint64) {
+	deviation := math.Abs(float64(estimate)-float64(want)) / float64(want)
+	assert.Less(t, deviation, 3*1.04/math.Sqrt(hllRegisters), "estimate %d of %d", estimate, want)
+}
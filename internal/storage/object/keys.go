@@ -0,0 +1,43 @@
+package object
+
+// Keys is persistent ordered set of strings, collections use it as index of their keys,
+// so scan seeks to cursor instead of sorting all keys
+type Keys struct {
+	tree treap[string, struct{}]
+}
+
+func NewKeys() Keys {
+	return Keys{tree: newTreap[string, struct{}](lessString)}
+}
+
+func (k Keys) Len() int {
+	return k.tree.len()
+}
+
+// Add returns set w key
+func (k Keys) Add(key string) Keys {
+	k.tree = k.tree.set(key, struct{}{})
+	return k
+}
+
+// Remove returns set w/o key
+func (k Keys) Remove(key string) Keys {
+	k.tree, _ = k.tree.delete(key)
+	return k
+}
+
+// AscendAfter calls fn for keys greater than after in order while fn returns true,
+// w/o after fn is called for all keys
+func (k Keys) AscendAfter(after string, hasAfter bool, fn func(key string) bool) {
+	start := 0
+	if hasAfter {
+		start = k.tree.rank(after)
+		if _, ok := k.tree.get(after); ok {
+			start++
+		}
+	}
+
+	k.tree.ascend(start, func(key string, _ struct{}) bool {
+		return fn(key)
+	})
+}
@@ -0,0 +1,28 @@
+package object
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeys(t *testing.T) {
+	keys := NewKeys().Add("b").Add("d").Add("a").Add("c")
+	removed := keys.Remove("c")
+	assert.Equal(t, 4, keys.Len())
+	assert.Equal(t, 3, removed.Len())
+
+	ascend := func(keys Keys, after string, hasAfter bool, limit int) []string {
+		var ascended []string
+		keys.AscendAfter(after, hasAfter, func(key string) bool {
+			ascended = append(ascended, key)
+			return len(ascended) < limit
+		})
+		return ascended
+	}
+	assert.Equal(t, []string{"a", "b", "c", "d"}, ascend(keys, "", false, 10))
+	assert.Equal(t, []string{"c", "d"}, ascend(keys, "b", true, 10))
+	assert.Equal(t, []string{"b", "c"}, ascend(keys, "aa", true, 2))
+	assert.Equal(t, []string{"d"}, ascend(removed, "b", true, 10))
+	assert.Empty(t, ascend(keys, "d", true, 10))
+}
@@ -0,0 +1,158 @@
+package object
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// List is value of list object, values are kept in persistent treap by their positions,
+// so pushes and pops are O(log n) and share nodes w source list
+type List struct {
+	items treap[int64, []byte]
+	size  int
+}
+
+func (l List) Type() Type {
+	return TypeList
+}
+
+func (l List) Len() int {
+	return l.items.len()
+}
+
+func (l List) Size() int {
+	return l.size
+}
+
+func (l List) MarshalJSON() ([]byte, error) {
+	return json.Marshal(l.Range(0, -1))
+}
+
+// Push returns list w values added one by one to the head or the tail,
+// so the last value is the head after push to the head
+func (l List) Push(head bool, values ...[]byte) List {
+	if l.items.less == nil {
+		l.items = newTreap[int64, []byte](lessInt64)
+	}
+
+	for _, value := range values {
+		// positions of the head decrease, positions of the tail increase
+		var position int64
+		switch {
+		case l.items.len() == 0:
+		case head:
+			first, _ := l.items.at(0)
+			position = first - 1
+		default:
+			last, _ := l.items.at(l.items.len() - 1)
+			position = last + 1
+		}
+
+		l.items = l.items.set(position, value)
+		l.size += len(value)
+	}
+	return l
+}
+
+// Pop returns list w/o count values from the head or the tail and popped values in order of popping,
+// so values popped from the tail are reversed, values are deleted by rank w/o changing source list
+func (l List) Pop(head bool, count int) (List, [][]byte) {
+	count = min(count, l.items.len())
+	popped := make([][]byte, count)
+	for i := range popped {
+		rank := 0
+		if !head {
+			rank = l.items.len() - 1
+		}
+
+		position, value := l.items.at(rank)
+		l.items, _ = l.items.delete(position)
+		l.size -= len(value)
+		popped[i] = value
+	}
+	return l, popped
+}
+
+// Range returns values from start to stop inclusive, negative index is counted from the tail
+func (l List) Range(start, stop int) [][]byte {
+	from, to := listBounds(start, stop, l.items.len())
+	values := make([][]byte, 0, to-from)
+	l.items.ascend(from, func(_ int64, value []byte) bool {
+		if len(values) == to-from {
+			return false
+		}
+		values = append(values, value)
+		return true
+	})
+	return values
+}
+
+// Trim returns list w values from start to stop inclusive only
+func (l List) Trim(start, stop int) List {
+	from, to := listBounds(start, stop, l.items.len())
+	trimmed := List{items: l.items.slice(from, to)}
+	trimmed.items.ascend(0, func(_ int64, value []byte) bool {
+		trimmed.size += len(value)
+		return true
+	})
+	return trimmed
+}
+
+// Index returns value by index, negative index is counted from the tail
+func (l List) Index(index int) ([]byte, bool) {
+	if index < 0 {
+		index += l.items.len()
+	}
+	if index < 0 || index >= l.items.len() {
+		return nil, false
+	}
+	_, value := l.items.at(index)
+	return value, true
+}
+
+// Remove returns list w/o count values equal to value and count of removed values,
+// positive count removes from the head, negative from the tail, 0 removes all
+func (l List) Remove(count int, value []byte) (List, int) {
+	limit := count
+	if limit < 0 {
+		limit = -limit
+	}
+
+	var positions []int64
+	l.items.ascend(0, func(position int64, item []byte) bool {
+		if bytes.Equal(item, value) {
+			positions = append(positions, position)
+		}
+		return true
+	})
+	if count < 0 {
+		// the last values are removed first
+		for i, j := 0, len(positions)-1; i < j; i, j = i+1, j-1 {
+			positions[i], positions[j] = positions[j], positions[i]
+		}
+	}
+	if limit > 0 && len(positions) > limit {
+		positions = positions[:limit]
+	}
+
+	for _, position := range positions {
+		l.items, _ = l.items.delete(position)
+	}
+	l.size -= len(positions) * len(value)
+	return l, len(positions)
+}
+
+// listBounds converts inclusive indexes to slice bounds, negative index is counted from the tail
+func listBounds(start, stop, length int) (int, int) {
+	if start < 0 {
+		start += length
+	}
+	if stop < 0 {
+		stop += length
+	}
+	start, stop = max(start, 0), min(stop, length-1)
+	if start > stop {
+		return 0, 0
+	}
+	return start, stop + 1
+}
@@ -0,0 +1,107 @@
+package object
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// values converts strings to list values
+func values(strings ...string) [][]byte {
+	converted := make([][]byte, len(strings))
+	for i, s := range strings {
+		converted[i] = []byte(s)
+	}
+	return converted
+}
+
+func TestList_Push(t *testing.T) {
+	list := List{}.Push(false, values("b", "c")...).Push(true, values("a", "0")...)
+	assert.Equal(t, values("0", "a", "b", "c"), list.Range(0, -1))
+	assert.Equal(t, 4, list.Len())
+	assert.Equal(t, 4, list.Size())
+}
+
+func TestList_Pop(t *testing.T) {
+	list := List{}.Push(false, values("a", "bb", "c")...)
+
+	popped, head := list.Pop(true, 1)
+	assert.Equal(t, values("a"), head)
+	assert.Equal(t, values("bb", "c"), popped.Range(0, -1))
+	assert.Equal(t, 3, popped.Size())
+
+	popped, tail := list.Pop(false, 5)
+	assert.Equal(t, values("c", "bb", "a"), tail)
+	assert.Equal(t, 0, popped.Len())
+	assert.Equal(t, 0, popped.Size())
+
+	// source list isn't changed
+	assert.Equal(t, values("a", "bb", "c"), list.Range(0, -1))
+
+	// pushes after pop don't change source list
+	pushed := popped.Push(true, values("d")...).Push(false, values("e")...)
+	assert.Equal(t, values("d", "e"), pushed.Range(0, -1))
+	assert.Equal(t, values("a", "bb", "c"), list.Range(0, -1))
+}
+
+func TestList_Range(t *testing.T) {
+	list := List{}.Push(false, values("a", "b", "c", "d")...)
+	tests := []struct {
+		name        string
+		start, stop int
+		want        [][]byte
+	}{
+		{name: "all", start: 0, stop: -1, want: values("a", "b", "c", "d")},
+		{name: "middle", start: 1, stop: 2, want: values("b", "c")},
+		{name: "negative", start: -2, stop: -1, want: values("c", "d")},
+		{name: "out of range", start: -10, stop: 10, want: values("a", "b", "c", "d")},
+		{name: "empty", start: 3, stop: 1, want: values()},
+		{name: "after end", start: 5, stop: 10, want: values()},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, list.Range(test.start, test.stop))
+			trimmed := list.Trim(test.start, test.stop)
+			assert.Equal(t, test.want, trimmed.Range(0, -1))
+			assert.Equal(t, len(test.want), trimmed.Size())
+		})
+	}
+}
+
+func TestList_Index(t *testing.T) {
+	list := List{}.Push(false, values("a", "b")...)
+
+	value, ok := list.Index(-1)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("b"), value)
+
+	_, ok = list.Index(2)
+	assert.False(t, ok)
+}
+
+func TestList_Remove(t *testing.T) {
+	list := List{}.Push(false, values("a", "x", "b", "x", "c", "x")...)
+	tests := []struct {
+		name        string
+		count       int
+		wantRemoved int
+		want        [][]byte
+	}{
+		{name: "all", count: 0, wantRemoved: 3, want: values("a", "b", "c")},
+		{name: "from head", count: 2, wantRemoved: 2, want: values("a", "b", "c", "x")},
+		{name: "from tail", count: -1, wantRemoved: 1, want: values("a", "x", "b", "x", "c")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			removed, count := list.Remove(test.count, []byte("x"))
+			assert.Equal(t, test.wantRemoved, count)
+			assert.Equal(t, test.want, removed.Range(0, -1))
+			assert.Equal(t, len(test.want), removed.Size())
+		})
+	}
+
+	_, count := list.Remove(0, []byte("unknown"))
+	assert.Equal(t, 0, count)
+}
@@ -4,19 +4,66 @@ import (
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"math"
 	"time"
+
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
 )
 
+// VersionExists is expected version which matches any existing object, like "If-Match: *"
+const VersionExists uint64 = math.MaxUint64
+
 type (
 	Object interface {
+		// Binary returns data of string object or json of data type value
 		Binary() []byte
+		// WithData returns copy of string object w new data and the same expiration
+		WithData(data []byte) Object
+		// Bits returns data of string object for bit operations
+		Bits() Bits
+		// WithBits returns copy of string object w new bits and the same expiration
+		WithBits(bits Bits) Object
+		Type() Type
+		// Value returns nil for string object
+		Value() Value
+		// WithValue returns copy of data type object w new value and the same expiration
+		WithValue(value Value) Object
+		// Version is set by collection on every write
+		Version() uint64
+		WithVersion(version uint64) Object
 		IsExpired() bool
+		Expires() time.Time
+		// WithExpires returns copy of object w new fixed expiration time
+		WithExpires(expires time.Time) Object
+		// TTL returns remaining time to live, -1 for timeless object
+		TTL() time.Duration
+		IsTimeless() bool
+		// IsSliding reports whether expiration is extended on every read
+		IsSliding() bool
+		// Slide returns copy of object w expiration extended by sliding window
+		Slide() Object
+		Size() int
+
+		// access metadata for eviction policies
+		Touch()
+		LastAccess() time.Time
+		Frequency() uint8
 	}
 
 	// simple implementation of object with expiration logic
 	object struct {
-		data    []byte
+		data []byte
+		// paged data of string object after setbit, nil for data w/o pages
+		bits *Bits
+		// value of data type object, nil for string object
+		value   Value
 		expires time.Time
+		version uint64
+		access  *access
+
+		// sliding window and hard expiration time of sliding object
+		sliding  time.Duration
+		deadline time.Time
 	}
 
 	Opt func(object) object
@@ -34,12 +81,30 @@ type (
 
 		// without expiration
 		Timeless bool `json:"timeless"`
+
+		// sliding window in seconds, object expires after window w/o reads,
+		// expiration by timeout, deadline or timeless is hard limit of lifetime
+		Sliding time.Duration `json:"sliding"`
+
+		// expected version of existing object, 0 - any, VersionExists - any existing object
+		Version uint64 `json:"version"`
+
+		// conditional write modes
+		// set only if object doesn't exist
+		NX bool `json:"nx"`
+		// set only if object exists
+		XX bool `json:"xx"`
+		// return previous object
+		Get bool `json:"get"`
+		// keep expiration of existing object
+		KeepTTL bool `json:"keepttl"`
 	}
 )
 
 func New(data []byte, opts ...Opt) Object {
 	object := object{
-		data: data,
+		data:   data,
+		access: newAccess(),
 	}
 
 	for _, opt := range opts {
@@ -50,15 +115,46 @@ func New(data []byte, opts ...Opt) Object {
 }
 
 func (s RequestSettings) New(defaultTimeout time.Duration) Object {
+	var opt Opt
 	switch {
 	case s.Timeout != 0:
-		return New(s.Data, WithTimeout(s.Timeout*time.Second))
+		opt = WithTimeout(s.Timeout * time.Second)
 	case !s.Deadline.IsZero():
-		return New(s.Data, WithDeadline(s.Deadline))
+		opt = WithDeadline(s.Deadline)
 	case s.Timeless:
-		return New(s.Data, WithoutTimeout())
+		opt = WithoutTimeout()
+	default:
+		opt = WithTimeout(defaultTimeout)
+	}
+
+	if s.Sliding > 0 {
+		return New(s.Data, opt, WithSliding(s.Sliding*time.Second))
+	}
+	return New(s.Data, opt)
+}
+
+// Validate checks that settings options don't conflict
+func (s RequestSettings) Validate() error {
+	switch {
+	case s.NX && s.XX:
+		return errors.ErrConflictFields("nx", "xx")
+	case s.KeepTTL && s.HasExpiration():
+		return errors.ErrConflictFields("keepttl", "expiration options")
+	case s.Sliding < 0:
+		return errors.ErrNegativeField("sliding")
+	default:
+		return nil
 	}
-	return New(s.Data, WithTimeout(defaultTimeout))
+}
+
+// HasExpiration reports whether any expiration option is set
+func (s RequestSettings) HasExpiration() bool {
+	return s.HasLifetime() || s.Sliding != 0
+}
+
+// HasLifetime reports whether timeout, deadline or timeless option is set
+func (s RequestSettings) HasLifetime() bool {
+	return s.Timeout != 0 || !s.Deadline.IsZero() || s.Timeless
 }
 
 func (s RequestSettings) NewKey() (string, error) {
@@ -72,9 +168,127 @@ func (s RequestSettings) NewKey() (string, error) {
 }
 
 func (o object) Binary() []byte {
+	if o.value != nil {
+		data, _ := json.Marshal(o.value)
+		return data
+	}
+	if o.bits != nil {
+		return o.bits.Bytes()
+	}
 	return o.data
 }
 
+func (o object) WithData(data []byte) Object {
+	o.data, o.bits, o.value = data, nil, nil
+	return o
+}
+
+func (o object) Bits() Bits {
+	if o.bits != nil {
+		return *o.bits
+	}
+	return NewBits(o.data)
+}
+
+func (o object) WithBits(bits Bits) Object {
+	o.data, o.bits, o.value = nil, &bits, nil
+	return o
+}
+
+func (o object) Type() Type {
+	if o.value != nil {
+		return o.value.Type()
+	}
+	return TypeString
+}
+
+func (o object) Value() Value {
+	return o.value
+}
+
+func (o object) WithValue(value Value) Object {
+	o.data, o.bits, o.value = nil, nil, value
+	return o
+}
+
+func (o object) Version() uint64 {
+	return o.version
+}
+
+// WithVersion returns copy of object w version
+func (o object) WithVersion(version uint64) Object {
+	o.version = version
+	return o
+}
+
+// Size returns count of data bytes
+func (o object) Size() int {
+	if o.value != nil {
+		return o.value.Size()
+	}
+	if o.bits != nil {
+		return o.bits.Len()
+	}
+	return len(o.data)
+}
+
+func (o object) Expires() time.Time {
+	return o.expires
+}
+
+func (o object) WithExpires(expires time.Time) Object {
+	o.expires = expires
+	o.sliding, o.deadline = 0, time.Time{}
+	return o
+}
+
+func (o object) TTL() time.Duration {
+	if o.IsTimeless() {
+		return -1
+	}
+	return max(time.Until(o.expires), 0)
+}
+
+// IsTimeless reports whether object never expires
+func (o object) IsTimeless() bool {
+	return o.expires.Equal(interstellar)
+}
+
+func (o object) IsSliding() bool {
+	return o.sliding > 0
+}
+
+func (o object) Slide() Object {
+	if o.IsSliding() {
+		o.expires = slidingExpires(o.sliding, o.deadline)
+	}
+	return o
+}
+
+// slidingExpires returns the end of sliding window from now but not later than deadline
+func slidingExpires(sliding time.Duration, deadline time.Time) time.Time {
+	expires := time.Now().Add(sliding)
+	if expires.After(deadline) {
+		return deadline
+	}
+	return expires
+}
+
+// Touch registers access to object
+func (o object) Touch() {
+	o.access.touch()
+}
+
+// LastAccess returns zero time if object wasn't touched
+func (o object) LastAccess() time.Time {
+	return o.access.lastAccess()
+}
+
+// Frequency returns logarithmic access counter decayed by time w/o accesses
+func (o object) Frequency() uint8 {
+	return o.access.decayedFrequency(time.Now())
+}
+
 func (o object) IsExpired() bool {
 	now := time.Now()
 	return o.expires.Before(now)
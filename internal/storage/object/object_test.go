@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var testDefaultTimeout = time.Second * 2
@@ -105,3 +106,56 @@ func TestObject_IsExpired(t *testing.T) {
 		})
 	}
 }
+
+func TestObject_Frequency(t *testing.T) {
+	obj := New([]byte("1"), WithoutTimeout())
+	assert.Equal(t, uint8(frequencyInit), obj.Frequency())
+	assert.True(t, obj.LastAccess().IsZero())
+
+	for i := 0; i < 1000; i++ {
+		obj.Touch()
+	}
+	assert.Greater(t, obj.Frequency(), uint8(frequencyInit))
+	assert.False(t, obj.LastAccess().IsZero())
+
+	// counter decays by one every period w/o accesses
+	a := newAccess()
+	a.frequency.Store(10)
+	a.accessed.Store(time.Now().Add(-3 * frequencyDecayPeriod).UnixNano())
+	assert.Equal(t, uint8(7), a.decayedFrequency(time.Now()))
+}
+
+func TestObject_IsTimeless(t *testing.T) {
+	assert.True(t, New(nil, WithoutTimeout()).IsTimeless())
+	assert.False(t, New(nil, WithTimeout(time.Hour)).IsTimeless())
+}
+
+func TestObject_TTL(t *testing.T) {
+	assert.Equal(t, time.Duration(-1), New(nil, WithoutTimeout()).TTL())
+	assert.Equal(t, time.Duration(0), New(nil, WithTimeout(-time.Second)).TTL())
+	assert.InDelta(t, time.Hour, New(nil, WithTimeout(time.Hour)).TTL(), float64(time.Second))
+
+	obj := New(nil, WithoutTimeout()).WithExpires(time.Now().Add(time.Minute))
+	assert.False(t, obj.IsTimeless())
+	assert.InDelta(t, time.Minute, obj.TTL(), float64(time.Second))
+}
+
+func TestObject_Slide(t *testing.T) {
+	obj := RequestSettings{Timeout: 2, Sliding: 1}.New(0)
+	require.True(t, obj.IsSliding())
+	assert.InDelta(t, time.Second, obj.TTL(), float64(100*time.Millisecond))
+
+	// read extends expiration by window
+	time.Sleep(500 * time.Millisecond)
+	obj = obj.Slide()
+	assert.InDelta(t, time.Second, obj.TTL(), float64(100*time.Millisecond))
+
+	// but not later than hard limit
+	time.Sleep(time.Second)
+	obj = obj.Slide()
+	assert.InDelta(t, 500*time.Millisecond, obj.TTL(), float64(100*time.Millisecond))
+
+	// fixed expiration disables sliding
+	assert.False(t, obj.WithExpires(time.Now()).IsSliding())
+	assert.False(t, New(nil, WithTimeout(time.Hour)).Slide().IsSliding())
+}
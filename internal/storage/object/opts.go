@@ -28,3 +28,13 @@ func WithoutTimeout() Opt {
 		return o
 	}
 }
+
+// WithSliding makes object expire after sliding window w/o reads,
+// expiration set by previous opts is kept as hard limit of lifetime
+func WithSliding(sliding time.Duration) Opt {
+	return func(o object) object {
+		o.sliding, o.deadline = sliding, o.expires
+		o.expires = slidingExpires(sliding, o.deadline)
+		return o
+	}
+}
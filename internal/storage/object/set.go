@@ -0,0 +1,136 @@
+package object
+
+import (
+	"encoding/json"
+	"math/rand"
+)
+
+// Set is value of set object, members are kept in persistent treap,
+// so writes copy only changed paths of tree
+type Set struct {
+	members treap[string, struct{}]
+	size    int
+}
+
+func (s Set) Type() Type {
+	return TypeSet
+}
+
+func (s Set) Len() int {
+	return s.members.len()
+}
+
+// Size returns count of members bytes
+func (s Set) Size() int {
+	return s.size
+}
+
+func (s Set) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Members())
+}
+
+// Add returns set w members and count of added members
+func (s Set) Add(members ...string) (Set, int) {
+	added := 0
+	for _, member := range members {
+		if !s.Has(member) {
+			s = s.add(member)
+			added++
+		}
+	}
+	return s, added
+}
+
+// Remove returns set w/o members and count of removed members
+func (s Set) Remove(members ...string) (Set, int) {
+	removed := 0
+	for _, member := range members {
+		var ok bool
+		if s.members, ok = s.members.delete(member); ok {
+			s.size -= len(member)
+			removed++
+		}
+	}
+	return s, removed
+}
+
+// Pop returns set w/o count random members and popped members
+func (s Set) Pop(count int) (Set, []string) {
+	popped := make([]string, 0, min(count, s.members.len()))
+	for len(popped) < cap(popped) {
+		member, _ := s.members.at(rand.Intn(s.members.len()))
+		s.members, _ = s.members.delete(member)
+		s.size -= len(member)
+		popped = append(popped, member)
+	}
+	return s, popped
+}
+
+func (s Set) Has(member string) bool {
+	_, ok := s.members.get(member)
+	return ok
+}
+
+// Members returns sorted members
+func (s Set) Members() []string {
+	members := make([]string, 0, s.members.len())
+	s.members.ascend(0, func(member string, _ struct{}) bool {
+		members = append(members, member)
+		return true
+	})
+	return members
+}
+
+// Inter returns members of set which are in all others
+func (s Set) Inter(others ...Set) Set {
+	var result Set
+	s.members.ascend(0, func(member string, _ struct{}) bool {
+		for _, other := range others {
+			if !other.Has(member) {
+				return true
+			}
+		}
+		result = result.add(member)
+		return true
+	})
+	return result
+}
+
+// Union returns members of set and all others
+func (s Set) Union(others ...Set) Set {
+	result := s
+	for _, other := range others {
+		other.members.ascend(0, func(member string, _ struct{}) bool {
+			if !result.Has(member) {
+				result = result.add(member)
+			}
+			return true
+		})
+	}
+	return result
+}
+
+// Diff returns members of set which aren't in any of others
+func (s Set) Diff(others ...Set) Set {
+	var result Set
+	s.members.ascend(0, func(member string, _ struct{}) bool {
+		for _, other := range others {
+			if other.Has(member) {
+				return true
+			}
+		}
+		result = result.add(member)
+		return true
+	})
+	return result
+}
+
+// add returns set w new member, member should be missing in set
+func (s Set) add(member string) Set {
+	if s.members.less == nil {
+		s.members = newTreap[string, struct{}](lessString)
+	}
+	s.members = s.members.set(member, struct{}{})
+	s.size += len(member)
+	return s
+}
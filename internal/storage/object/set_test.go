@@ -0,0 +1,47 @@
+package object
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSet(t *testing.T) {
+	set, added := Set{}.Add("a", "b", "b", "cc")
+	assert.Equal(t, 3, added)
+	assert.Equal(t, 3, set.Len())
+	assert.Equal(t, 4, set.Size())
+
+	removed, count := set.Remove("a", "unknown")
+	assert.Equal(t, 1, count)
+	assert.Equal(t, []string{"b", "cc"}, removed.Members())
+	assert.Equal(t, 3, removed.Size())
+
+	// source set isn't changed
+	assert.True(t, set.Has("a"))
+
+	popped, members := set.Pop(2)
+	assert.Len(t, members, 2)
+	assert.Equal(t, 1, popped.Len())
+	for _, member := range members {
+		assert.True(t, set.Has(member))
+		assert.False(t, popped.Has(member))
+	}
+	assert.Equal(t, 3, set.Len())
+
+	empty, count := Set{}.Remove("a")
+	assert.Equal(t, 0, count)
+	assert.Equal(t, 0, empty.Len())
+}
+
+func TestSet_Algebra(t *testing.T) {
+	first, _ := Set{}.Add("a", "b", "c")
+	second, _ := Set{}.Add("b", "c", "d")
+	third, _ := Set{}.Add("c", "e")
+
+	assert.Equal(t, []string{"c"}, first.Inter(second, third).Members())
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, first.Union(second, third).Members())
+	assert.Equal(t, []string{"a"}, first.Diff(second, third).Members())
+	assert.Empty(t, first.Inter(Set{}).Members())
+	assert.Equal(t, 2, first.Diff(third).Size())
+}
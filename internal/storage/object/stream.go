@@ -0,0 +1,379 @@
+package object
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+)
+
+// size of stream id
+const streamIDSize = 16
+
+// bounds of stream ranges
+var (
+	MinStreamID = StreamID{}
+	MaxStreamID = StreamID{Ms: math.MaxUint64, Seq: math.MaxUint64}
+)
+
+type (
+	// StreamID is unique id of stream entry, ms is time of adding entry in milliseconds,
+	// seq is sequence number of entries added in the same millisecond
+	StreamID struct {
+		Ms  uint64
+		Seq uint64
+	}
+
+	// StreamEntry is entry of stream w id in "ms-seq" format
+	StreamEntry struct {
+		ID     string            `json:"id"`
+		Fields map[string][]byte `json:"fields"`
+	}
+
+	// Stream is value of append-only log object w consumer groups,
+	// entries and pending entries are stored in persistent treaps, so writes are logarithmic
+	Stream struct {
+		entries treap[StreamID, map[string][]byte]
+		lastID  StreamID
+		groups  map[string]consumerGroup
+		size    int
+	}
+
+	// consumerGroup tracks the last delivered entry and delivered but not acknowledged entries
+	consumerGroup struct {
+		lastDelivered StreamID
+		pending       treap[StreamID, PendingEntry]
+	}
+
+	// PendingEntry is entry delivered to consumer but not acknowledged
+	PendingEntry struct {
+		ID         string    `json:"id"`
+		Consumer   string    `json:"consumer"`
+		Delivered  time.Time `json:"delivered"`
+		Deliveries int       `json:"deliveries"`
+	}
+)
+
+func (s Stream) Type() Type {
+	return TypeStream
+}
+
+// Len returns count of entries and groups, so stream w groups isn't deleted when it has no entries
+func (s Stream) Len() int {
+	return s.entries.len() + len(s.groups)
+}
+
+// Size returns count of entries and pending entries bytes
+func (s Stream) Size() int {
+	return s.size
+}
+
+func (s Stream) MarshalJSON() ([]byte, error) {
+	type group struct {
+		LastDelivered string         `json:"last_delivered"`
+		Pending       []PendingEntry `json:"pending"`
+	}
+
+	groups := make(map[string]group, len(s.groups))
+	for name, g := range s.groups {
+		groups[name] = group{LastDelivered: g.lastDelivered.String(), Pending: g.pendingOf("", 0)}
+	}
+	return json.Marshal(struct {
+		LastID  string           `json:"last_id"`
+		Entries []StreamEntry    `json:"entries"`
+		Groups  map[string]group `json:"groups"`
+	}{
+		LastID:  s.lastID.String(),
+		Entries: s.Range(MinStreamID, MaxStreamID, 0),
+		Groups:  groups,
+	})
+}
+
+// Count returns count of entries
+func (s Stream) Count() int {
+	return s.entries.len()
+}
+
+// LastID returns id of the last added entry even if it's trimmed
+func (s Stream) LastID() StreamID {
+	return s.lastID
+}
+
+// Add returns stream w new entry and id of entry, "*" or empty id is generated from now,
+// id must be greater than id of the last entry
+func (s Stream) Add(id string, fields map[string][]byte, now time.Time) (Stream, StreamID, error) {
+	entryID, err := s.nextID(id, now)
+	if err != nil {
+		return s, StreamID{}, err
+	}
+
+	if s.entries.less == nil {
+		s.entries = newTreap[StreamID, map[string][]byte](StreamID.less)
+	}
+	s.entries = s.entries.set(entryID, fields)
+	s.lastID = entryID
+	s.size += entrySize(fields)
+	return s, entryID, nil
+}
+
+// Range returns up to count entries w ids from start to end inclusive, 0 count - all entries
+func (s Stream) Range(start, end StreamID, count int) []StreamEntry {
+	entries := make([]StreamEntry, 0)
+	s.entries.ascend(s.entries.rank(start), func(id StreamID, fields map[string][]byte) bool {
+		if end.less(id) {
+			return false
+		}
+		entries = append(entries, StreamEntry{ID: id.String(), Fields: fields})
+		return count == 0 || len(entries) < count
+	})
+	return entries
+}
+
+// TrimLen returns stream w/o the oldest entries over maxLen and count of removed entries
+func (s Stream) TrimLen(maxLen int) (Stream, int) {
+	removed := 0
+	for s.entries.len() > maxLen {
+		s = s.removeFirst()
+		removed++
+	}
+	return s, removed
+}
+
+// TrimBefore returns stream w/o entries w ids less than minID and count of removed entries
+func (s Stream) TrimBefore(minID StreamID) (Stream, int) {
+	removed := 0
+	for s.entries.len() > 0 {
+		if id, _ := s.entries.at(0); !id.less(minID) {
+			break
+		}
+		s = s.removeFirst()
+		removed++
+	}
+	return s, removed
+}
+
+// CreateGroup returns stream w new consumer group which delivers entries after lastDelivered id
+func (s Stream) CreateGroup(name string, lastDelivered StreamID) (Stream, error) {
+	if _, ok := s.groups[name]; ok {
+		return s, errors.ErrGroupExists(name)
+	}
+
+	s.groups = s.copyGroups()
+	s.groups[name] = consumerGroup{
+		lastDelivered: lastDelivered,
+		pending:       newTreap[StreamID, PendingEntry](StreamID.less),
+	}
+	return s, nil
+}
+
+// ReadGroup returns stream w up to count entries which were never delivered to group
+// added to pending entries of consumer and delivered entries, 0 count - all entries
+func (s Stream) ReadGroup(name, consumer string, count int, now time.Time) (Stream, []StreamEntry, error) {
+	group, ok := s.groups[name]
+	if !ok {
+		return s, nil, errors.ErrNoGroup(name)
+	}
+
+	entries := s.Range(group.lastDelivered.next(), MaxStreamID, count)
+	for _, entry := range entries {
+		id, _ := ParseStreamID(entry.ID, 0)
+		group.pending = group.pending.set(id, PendingEntry{ID: entry.ID, Consumer: consumer, Delivered: now, Deliveries: 1})
+		group.lastDelivered = id
+		s.size += pendingSize(consumer)
+	}
+	return s.withGroup(name, group), entries, nil
+}
+
+// History returns up to count pending entries of consumer w ids greater than after, 0 count - all entries,
+// entry deleted from stream has no fields
+func (s Stream) History(name, consumer string, after StreamID, count int) ([]StreamEntry, error) {
+	group, ok := s.groups[name]
+	if !ok {
+		return nil, errors.ErrNoGroup(name)
+	}
+
+	entries := make([]StreamEntry, 0)
+	group.pending.ascend(group.pending.rank(after.next()), func(id StreamID, pending PendingEntry) bool {
+		if pending.Consumer != consumer {
+			return true
+		}
+		fields, _ := s.entries.get(id)
+		entries = append(entries, StreamEntry{ID: pending.ID, Fields: fields})
+		return count == 0 || len(entries) < count
+	})
+	return entries, nil
+}
+
+// Ack returns stream w/o pending entries of group and count of acknowledged entries
+func (s Stream) Ack(name string, ids ...StreamID) (Stream, int, error) {
+	group, ok := s.groups[name]
+	if !ok {
+		return s, 0, errors.ErrNoGroup(name)
+	}
+
+	acked := 0
+	for _, id := range ids {
+		pending, ok := group.pending.get(id)
+		if !ok {
+			continue
+		}
+
+		group.pending, _ = group.pending.delete(id)
+		s.size -= pendingSize(pending.Consumer)
+		acked++
+	}
+	return s.withGroup(name, group), acked, nil
+}
+
+// Pending returns up to count pending entries of group in order of ids, 0 count - all entries,
+// empty consumer - entries of all consumers
+func (s Stream) Pending(name, consumer string, count int) ([]PendingEntry, error) {
+	group, ok := s.groups[name]
+	if !ok {
+		return nil, errors.ErrNoGroup(name)
+	}
+	return group.pendingOf(consumer, count), nil
+}
+
+// Claim returns stream w pending entries idle at least minIdle transferred to consumer and claimed entries,
+// pending entries deleted from stream are acknowledged
+func (s Stream) Claim(name, consumer string, minIdle time.Duration, ids []StreamID, now time.Time) (Stream, []StreamEntry, error) {
+	group, ok := s.groups[name]
+	if !ok {
+		return s, nil, errors.ErrNoGroup(name)
+	}
+
+	entries := make([]StreamEntry, 0, len(ids))
+	for _, id := range ids {
+		pending, ok := group.pending.get(id)
+		if !ok || now.Sub(pending.Delivered) < minIdle {
+			continue
+		}
+
+		s.size -= pendingSize(pending.Consumer)
+		fields, ok := s.entries.get(id)
+		if !ok {
+			group.pending, _ = group.pending.delete(id)
+			continue
+		}
+
+		pending.Consumer, pending.Delivered = consumer, now
+		pending.Deliveries++
+		group.pending = group.pending.set(id, pending)
+		s.size += pendingSize(consumer)
+		entries = append(entries, StreamEntry{ID: pending.ID, Fields: fields})
+	}
+	return s.withGroup(name, group), entries, nil
+}
+
+// nextID returns id of new entry
+func (s Stream) nextID(id string, now time.Time) (StreamID, error) {
+	if id == "" || id == "*" {
+		ms := uint64(max(now.UnixMilli(), 0))
+		if ms <= s.lastID.Ms {
+			return s.lastID.next(), nil
+		}
+		return StreamID{Ms: ms}, nil
+	}
+
+	entryID, err := ParseStreamID(id, 0)
+	if err != nil {
+		return StreamID{}, err
+	}
+	if !s.lastID.less(entryID) {
+		return StreamID{}, errors.ErrStreamIDTooSmall
+	}
+	return entryID, nil
+}
+
+func (s Stream) removeFirst() Stream {
+	id, fields := s.entries.at(0)
+	s.entries, _ = s.entries.delete(id)
+	s.size -= entrySize(fields)
+	return s
+}
+
+// withGroup returns stream w copy of groups where group is replaced
+func (s Stream) withGroup(name string, group consumerGroup) Stream {
+	s.groups = s.copyGroups()
+	s.groups[name] = group
+	return s
+}
+
+func (s Stream) copyGroups() map[string]consumerGroup {
+	groups := make(map[string]consumerGroup, len(s.groups)+1)
+	for name, group := range s.groups {
+		groups[name] = group
+	}
+	return groups
+}
+
+func (g consumerGroup) pendingOf(consumer string, count int) []PendingEntry {
+	entries := make([]PendingEntry, 0)
+	g.pending.ascend(0, func(_ StreamID, pending PendingEntry) bool {
+		if consumer != "" && pending.Consumer != consumer {
+			return true
+		}
+		entries = append(entries, pending)
+		return count == 0 || len(entries) < count
+	})
+	return entries
+}
+
+func entrySize(fields map[string][]byte) int {
+	size := streamIDSize
+	for field, value := range fields {
+		size += len(field) + len(value)
+	}
+	return size
+}
+
+func pendingSize(consumer string) int {
+	return streamIDSize + len(consumer)
+}
+
+// ParseStreamID parses id in "ms-seq" or "ms" format, seq is used for id w/o sequence number,
+// "-" and "+" are the minimal and the maximal ids
+func ParseStreamID(id string, seq uint64) (StreamID, error) {
+	switch id {
+	case "-":
+		return MinStreamID, nil
+	case "+":
+		return MaxStreamID, nil
+	}
+
+	msPart, seqPart, hasSeq := strings.Cut(id, "-")
+	ms, err := strconv.ParseUint(msPart, 10, 64)
+	if err != nil {
+		return StreamID{}, errors.ErrInvalidStreamID(id)
+	}
+	if hasSeq {
+		seq, err = strconv.ParseUint(seqPart, 10, 64)
+		if err != nil {
+			return StreamID{}, errors.ErrInvalidStreamID(id)
+		}
+	}
+	return StreamID{Ms: ms, Seq: seq}, nil
+}
+
+func (id StreamID) String() string {
+	return strconv.FormatUint(id.Ms, 10) + "-" + strconv.FormatUint(id.Seq, 10)
+}
+
+func (id StreamID) less(other StreamID) bool {
+	if id.Ms != other.Ms {
+		return id.Ms < other.Ms
+	}
+	return id.Seq < other.Seq
+}
+
+// next returns the least id greater than id
+func (id StreamID) next() StreamID {
+	if id.Seq == math.MaxUint64 {
+		return StreamID{Ms: id.Ms + 1}
+	}
+	return StreamID{Ms: id.Ms, Seq: id.Seq + 1}
+}
@@ -0,0 +1,145 @@
+package object
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+)
+
+func TestStream(t *testing.T) {
+	now := time.UnixMilli(1000)
+	fields := map[string][]byte{"event": []byte("created")}
+
+	stream, id, err := Stream{}.Add("*", fields, now)
+	require.Nil(t, err)
+	assert.Equal(t, StreamID{Ms: 1000}, id)
+
+	// id of entry in the same millisecond gets next sequence number
+	stream, id, err = stream.Add("", fields, now)
+	require.Nil(t, err)
+	assert.Equal(t, StreamID{Ms: 1000, Seq: 1}, id)
+
+	stream, id, err = stream.Add("2000-5", fields, now)
+	require.Nil(t, err)
+	assert.Equal(t, "2000-5", id.String())
+
+	_, _, err = stream.Add("2000-5", fields, now)
+	assert.Equal(t, errors.ErrStreamIDTooSmall, err)
+	_, _, err = stream.Add("2000-x", fields, now)
+	assert.Equal(t, errors.ErrInvalidStreamID("2000-x"), err)
+
+	assert.Equal(t, 3, stream.Count())
+	assert.Equal(t, 3*(streamIDSize+12), stream.Size())
+
+	entries := stream.Range(StreamID{Ms: 1000, Seq: 1}, MaxStreamID, 0)
+	assert.Equal(t, []StreamEntry{{ID: "1000-1", Fields: fields}, {ID: "2000-5", Fields: fields}}, entries)
+	assert.Len(t, stream.Range(MinStreamID, MaxStreamID, 2), 2)
+	assert.Empty(t, stream.Range(StreamID{Ms: 3000}, MaxStreamID, 0))
+
+	trimmed, removed := stream.TrimLen(1)
+	assert.Equal(t, 2, removed)
+	assert.Equal(t, "2000-5", trimmed.Range(MinStreamID, MaxStreamID, 0)[0].ID)
+	assert.Equal(t, streamIDSize+12, trimmed.Size())
+
+	trimmed, removed = stream.TrimBefore(StreamID{Ms: 2000})
+	assert.Equal(t, 2, removed)
+	assert.Equal(t, 1, trimmed.Count())
+
+	// source stream isn't changed, the last id is kept after trim
+	assert.Equal(t, 3, stream.Count())
+	trimmed, _ = trimmed.TrimLen(0)
+	assert.Equal(t, 0, trimmed.Len())
+	assert.Equal(t, StreamID{Ms: 2000, Seq: 5}, trimmed.LastID())
+}
+
+func TestStream_Groups(t *testing.T) {
+	now := time.UnixMilli(1000)
+	fields := map[string][]byte{"event": []byte("created")}
+
+	stream, _, _ := Stream{}.Add("1-0", fields, now)
+	stream, err := stream.CreateGroup("workers", stream.LastID())
+	require.Nil(t, err)
+	_, err = stream.CreateGroup("workers", MinStreamID)
+	assert.Equal(t, errors.ErrGroupExists("workers"), err)
+
+	// stream w group isn't empty
+	empty, _ := stream.TrimLen(0)
+	assert.Equal(t, 1, empty.Len())
+
+	stream, _, _ = stream.Add("2-0", fields, now)
+	stream, _, _ = stream.Add("3-0", fields, now)
+
+	stream, delivered, err := stream.ReadGroup("workers", "alice", 1, now)
+	require.Nil(t, err)
+	assert.Equal(t, []StreamEntry{{ID: "2-0", Fields: fields}}, delivered)
+	stream, delivered, err = stream.ReadGroup("workers", "bob", 0, now)
+	require.Nil(t, err)
+	assert.Equal(t, []StreamEntry{{ID: "3-0", Fields: fields}}, delivered)
+	_, delivered, err = stream.ReadGroup("workers", "bob", 0, now)
+	require.Nil(t, err)
+	assert.Empty(t, delivered)
+	_, _, err = stream.ReadGroup("unknown", "bob", 0, now)
+	assert.Equal(t, errors.ErrNoGroup("unknown"), err)
+
+	history, err := stream.History("workers", "alice", MinStreamID, 0)
+	require.Nil(t, err)
+	assert.Equal(t, []StreamEntry{{ID: "2-0", Fields: fields}}, history)
+
+	pending, err := stream.Pending("workers", "", 0)
+	require.Nil(t, err)
+	assert.Equal(t, []PendingEntry{
+		{ID: "2-0", Consumer: "alice", Delivered: now, Deliveries: 1},
+		{ID: "3-0", Consumer: "bob", Delivered: now, Deliveries: 1},
+	}, pending)
+
+	// entry isn't claimed before min idle time
+	later := now.Add(time.Minute)
+	_, claimed, err := stream.Claim("workers", "bob", 2*time.Minute, []StreamID{{Ms: 2}}, later)
+	require.Nil(t, err)
+	assert.Empty(t, claimed)
+
+	stream, claimed, err = stream.Claim("workers", "bob", time.Minute, []StreamID{{Ms: 2}}, later)
+	require.Nil(t, err)
+	assert.Equal(t, []StreamEntry{{ID: "2-0", Fields: fields}}, claimed)
+	pending, err = stream.Pending("workers", "bob", 1)
+	require.Nil(t, err)
+	assert.Equal(t, []PendingEntry{{ID: "2-0", Consumer: "bob", Delivered: later, Deliveries: 2}}, pending)
+
+	size := stream.Size()
+	stream, acked, err := stream.Ack("workers", StreamID{Ms: 2}, StreamID{Ms: 3}, StreamID{Ms: 4})
+	require.Nil(t, err)
+	assert.Equal(t, 2, acked)
+	assert.Equal(t, size-2*pendingSize("bob"), stream.Size())
+	pending, err = stream.Pending("workers", "", 0)
+	require.Nil(t, err)
+	assert.Empty(t, pending)
+}
+
+func TestParseStreamID(t *testing.T) {
+	tests := []struct {
+		id      string
+		seq     uint64
+		want    StreamID
+		wantErr error
+	}{
+		{id: "-", want: MinStreamID},
+		{id: "+", want: MaxStreamID},
+		{id: "5-3", want: StreamID{Ms: 5, Seq: 3}},
+		{id: "5", seq: 7, want: StreamID{Ms: 5, Seq: 7}},
+		{id: "5-", wantErr: errors.ErrInvalidStreamID("5-")},
+		{id: "-5", wantErr: errors.ErrInvalidStreamID("-5")},
+		{id: "", wantErr: errors.ErrInvalidStreamID("")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.id, func(t *testing.T) {
+			id, err := ParseStreamID(test.id, test.seq)
+			assert.Equal(t, test.wantErr, err)
+			assert.Equal(t, test.want, id)
+		})
+	}
+}
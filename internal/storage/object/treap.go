@@ -0,0 +1,202 @@
+package object
+
+import "math/rand"
+
+type (
+	// treap is persistent randomized balanced search tree w order statistics,
+	// writes copy only nodes on the path from root, so older treaps stay valid
+	// and share all other nodes w new one
+	treap[K, V any] struct {
+		root *treapNode[K, V]
+		less func(a, b K) bool
+	}
+
+	// treapNode is never changed after it's built
+	treapNode[K, V any] struct {
+		key         K
+		value       V
+		priority    uint32
+		size        int
+		left, right *treapNode[K, V]
+	}
+)
+
+func newTreap[K, V any](less func(a, b K) bool) treap[K, V] {
+	return treap[K, V]{less: less}
+}
+
+func (t treap[K, V]) len() int {
+	return t.root.count()
+}
+
+func (t treap[K, V]) get(key K) (V, bool) {
+	node := t.root
+	for node != nil {
+		switch {
+		case t.less(key, node.key):
+			node = node.left
+		case t.less(node.key, key):
+			node = node.right
+		default:
+			return node.value, true
+		}
+	}
+
+	var empty V
+	return empty, false
+}
+
+// set returns treap w value of key
+func (t treap[K, V]) set(key K, value V) treap[K, V] {
+	left, rest := t.split(t.root, key, false)
+	_, right := t.split(rest, key, true)
+	node := &treapNode[K, V]{key: key, value: value, priority: rand.Uint32(), size: 1}
+	t.root = t.merge(t.merge(left, node), right)
+	return t
+}
+
+// delete returns treap w/o key and reports whether key was in treap
+func (t treap[K, V]) delete(key K) (treap[K, V], bool) {
+	left, rest := t.split(t.root, key, false)
+	deleted, right := t.split(rest, key, true)
+	if deleted == nil {
+		return t, false
+	}
+	t.root = t.merge(left, right)
+	return t, true
+}
+
+// rank returns count of keys less than key
+func (t treap[K, V]) rank(key K) int {
+	rank := 0
+	node := t.root
+	for node != nil {
+		if t.less(node.key, key) {
+			rank += node.left.count() + 1
+			node = node.right
+		} else {
+			node = node.left
+		}
+	}
+	return rank
+}
+
+// at returns key and value by rank, rank should be in range
+func (t treap[K, V]) at(rank int) (K, V) {
+	node := t.root
+	for {
+		leftSize := node.left.count()
+		switch {
+		case rank < leftSize:
+			node = node.left
+		case rank > leftSize:
+			rank -= leftSize + 1
+			node = node.right
+		default:
+			return node.key, node.value
+		}
+	}
+}
+
+// lessString orders string keys
+func lessString(a, b string) bool {
+	return a < b
+}
+
+// lessInt orders int keys
+func lessInt(a, b int) bool {
+	return a < b
+}
+
+// lessInt64 orders int64 keys
+func lessInt64(a, b int64) bool {
+	return a < b
+}
+
+// slice returns treap of keys w ranks from start to stop exclusive, ranks should be in range
+func (t treap[K, V]) slice(start, stop int) treap[K, V] {
+	if start >= stop {
+		t.root = nil
+		return t
+	}
+	if stop < t.len() {
+		key, _ := t.at(stop)
+		t.root, _ = t.split(t.root, key, false)
+	}
+	if start > 0 {
+		key, _ := t.at(start)
+		_, t.root = t.split(t.root, key, false)
+	}
+	return t
+}
+
+// ascend calls fn for keys w ranks from start to the end in order while fn returns true
+func (t treap[K, V]) ascend(start int, fn func(key K, value V) bool) {
+	t.root.ascend(start, fn)
+}
+
+// split returns treaps of keys less than key and the rest,
+// w inclusive left treap also has keys equal to key
+func (t treap[K, V]) split(node *treapNode[K, V], key K, inclusive bool) (*treapNode[K, V], *treapNode[K, V]) {
+	if node == nil {
+		return nil, nil
+	}
+
+	toLeft := t.less(node.key, key) || (inclusive && !t.less(key, node.key))
+	copied := *node
+	if toLeft {
+		left, right := t.split(node.right, key, inclusive)
+		copied.right = left
+		return copied.resize(), right
+	}
+	left, right := t.split(node.left, key, inclusive)
+	copied.left = right
+	return left, copied.resize()
+}
+
+// merge returns treap of all keys, keys of left should be less than keys of right
+func (t treap[K, V]) merge(left, right *treapNode[K, V]) *treapNode[K, V] {
+	switch {
+	case left == nil:
+		return right
+	case right == nil:
+		return left
+	case left.priority > right.priority:
+		copied := *left
+		copied.right = t.merge(left.right, right)
+		return copied.resize()
+	default:
+		copied := *right
+		copied.left = t.merge(left, right.left)
+		return copied.resize()
+	}
+}
+
+func (n *treapNode[K, V]) count() int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+// resize updates size of new node and returns it
+func (n *treapNode[K, V]) resize() *treapNode[K, V] {
+	n.size = n.left.count() + n.right.count() + 1
+	return n
+}
+
+// ascend skips subtrees before start by their sizes, returns false if fn stopped iteration
+func (n *treapNode[K, V]) ascend(start int, fn func(key K, value V) bool) bool {
+	if n == nil {
+		return true
+	}
+
+	leftSize := n.left.count()
+	if start < leftSize && !n.left.ascend(start, fn) {
+		return false
+	}
+	if start <= leftSize && !fn(n.key, n.value) {
+		return false
+	}
+	return n.right.ascend(max(start-leftSize-1, 0), fn)
+}
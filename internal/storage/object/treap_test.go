@@ -0,0 +1,90 @@
+package object
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTreap(t *testing.T) {
+	tree := newTreap[int, int](func(a, b int) bool { return a < b })
+	model := make(map[int]int)
+
+	// every version of treap stays valid after writes
+	versions := []treap[int, int]{tree}
+	models := []map[int]int{{}}
+	for i := 0; i < 2000; i++ {
+		key := rand.Intn(300)
+		if rand.Intn(3) == 0 {
+			var deleted bool
+			tree, deleted = tree.delete(key)
+			_, ok := model[key]
+			require.Equal(t, ok, deleted)
+			delete(model, key)
+		} else {
+			tree = tree.set(key, i)
+			model[key] = i
+		}
+
+		if i%100 == 0 {
+			snapshot := make(map[int]int, len(model))
+			for k, v := range model {
+				snapshot[k] = v
+			}
+			versions, models = append(versions, tree), append(models, snapshot)
+		}
+	}
+
+	for i, version := range versions {
+		assertTreap(t, version, models[i])
+	}
+	assertTreap(t, tree, model)
+}
+
+// assertTreap checks treap against model map
+func assertTreap(t *testing.T, tree treap[int, int], model map[int]int) {
+	keys := make([]int, 0, len(model))
+	for key := range model {
+		keys = append(keys, key)
+	}
+	sort.Ints(keys)
+	require.Equal(t, len(keys), tree.len())
+
+	for rank, key := range keys {
+		value, ok := tree.get(key)
+		assert.True(t, ok)
+		assert.Equal(t, model[key], value)
+		assert.Equal(t, rank, tree.rank(key))
+
+		atKey, atValue := tree.at(rank)
+		assert.Equal(t, key, atKey)
+		assert.Equal(t, model[key], atValue)
+	}
+
+	// ascend from the middle
+	start := len(keys) / 2
+	var ascended []int
+	tree.ascend(start, func(key, _ int) bool {
+		ascended = append(ascended, key)
+		return true
+	})
+	if start < len(keys) {
+		assert.Equal(t, keys[start:], ascended)
+	}
+
+	// slice of the middle half
+	from, to := len(keys)/4, len(keys)*3/4
+	sliced := tree.slice(from, to)
+	var slicedKeys []int
+	sliced.ascend(0, func(key, _ int) bool {
+		slicedKeys = append(slicedKeys, key)
+		return true
+	})
+	require.Equal(t, to-from, sliced.len())
+	if from < to {
+		assert.Equal(t, keys[from:to], slicedKeys)
+	}
+}
@@ -0,0 +1,28 @@
+package object
+
+// types of objects
+const (
+	TypeString      Type = "string"
+	TypeList        Type = "list"
+	TypeHash        Type = "hash"
+	TypeSet         Type = "set"
+	TypeZSet        Type = "zset"
+	TypeStream      Type = "stream"
+	TypeHyperLogLog Type = "hyperloglog"
+)
+
+type (
+	// Type of object value
+	Type string
+
+	// Value is value of data type object, it's immutable,
+	// so it's safe to read value of object w/o collection lock,
+	// operations return new value sharing nothing mutable w old one
+	Value interface {
+		Type() Type
+		// Len returns count of elements, object w empty value is deleted
+		Len() int
+		// Size returns count of data bytes
+		Size() int
+	}
+)
@@ -0,0 +1,229 @@
+package object
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+)
+
+// size of score of sorted set member
+const scoreSize = 8
+
+type (
+	// ZSet is value of sorted set object, members are ordered by score and then by member,
+	// it's built on persistent treaps, so writes are logarithmic and don't change source set
+	ZSet struct {
+		scores  treap[string, float64]
+		ordered treap[ScoredMember, struct{}]
+		size    int
+	}
+
+	// ScoredMember is member of sorted set w score
+	ScoredMember struct {
+		Member string  `json:"member"`
+		Score  float64 `json:"score"`
+	}
+
+	// ZAddOptions are conditions of update of existing members and adding new ones
+	ZAddOptions struct {
+		// only add new members
+		NX bool `json:"nx"`
+		// only update existing members
+		XX bool `json:"xx"`
+		// update existing member only if new score is greater
+		GT bool `json:"gt"`
+		// update existing member only if new score is less
+		LT bool `json:"lt"`
+	}
+
+	// ScoreBound is min or max of scores range
+	ScoreBound struct {
+		Score     float64
+		Exclusive bool
+	}
+)
+
+func (z ZSet) Type() Type {
+	return TypeZSet
+}
+
+func (z ZSet) Len() int {
+	return z.scores.len()
+}
+
+// Size returns count of members and scores bytes
+func (z ZSet) Size() int {
+	return z.size
+}
+
+func (z ZSet) MarshalJSON() ([]byte, error) {
+	return json.Marshal(z.Range(0, -1))
+}
+
+// Add returns sorted set w members which meet options, count of added members
+// and count of changed members (added or w new score)
+func (z ZSet) Add(members []ScoredMember, options ZAddOptions) (zset ZSet, added, changed int) {
+	for _, member := range members {
+		current, exists := z.scores.get(member.Member)
+		switch {
+		case exists && options.NX, !exists && options.XX:
+			continue
+		case exists && options.GT && member.Score <= current, exists && options.LT && member.Score >= current:
+			continue
+		case exists && member.Score == current:
+			continue
+		}
+
+		z = z.set(member.Member, member.Score)
+		changed++
+		if !exists {
+			added++
+		}
+	}
+	return z, added, changed
+}
+
+// Incr returns sorted set w score of member increased by increment and new score,
+// missing member is added w increment score
+func (z ZSet) Incr(member string, increment float64) (ZSet, float64, error) {
+	current, _ := z.scores.get(member)
+	score := current + increment
+	if math.IsNaN(score) || math.IsInf(score, 0) {
+		return z, 0, errors.ErrIncrementNaN
+	}
+	return z.set(member, score), score, nil
+}
+
+func (z ZSet) Score(member string) (float64, bool) {
+	return z.scores.get(member)
+}
+
+// Rank returns rank of member in order of scores from the lowest
+func (z ZSet) Rank(member string) (int, bool) {
+	score, ok := z.scores.get(member)
+	if !ok {
+		return 0, false
+	}
+	return z.ordered.rank(ScoredMember{Member: member, Score: score}), true
+}
+
+// Range returns members w ranks from start to stop inclusive, negative rank is counted from the highest score
+func (z ZSet) Range(start, stop int) []ScoredMember {
+	from, to := listBounds(start, stop, z.Len())
+	members := make([]ScoredMember, 0, to-from)
+	z.ordered.ascend(from, func(member ScoredMember, _ struct{}) bool {
+		if len(members) == to-from {
+			return false
+		}
+		members = append(members, member)
+		return true
+	})
+	return members
+}
+
+// RangeByScore returns up to count members w scores between min and max, 0 count - all members
+func (z ZSet) RangeByScore(min, max ScoreBound, count int) []ScoredMember {
+	var members []ScoredMember
+	// the empty member is the first one of members w the same score
+	start := z.ordered.rank(ScoredMember{Score: min.Score})
+	z.ordered.ascend(start, func(member ScoredMember, _ struct{}) bool {
+		if min.Exclusive && member.Score == min.Score {
+			return true
+		}
+		if member.Score > max.Score || (max.Exclusive && member.Score == max.Score) {
+			return false
+		}
+		members = append(members, member)
+		return count == 0 || len(members) < count
+	})
+	return members
+}
+
+// Remove returns sorted set w/o members and count of removed members
+func (z ZSet) Remove(members ...string) (ZSet, int) {
+	removed := 0
+	for _, member := range members {
+		score, ok := z.scores.get(member)
+		if !ok {
+			continue
+		}
+
+		z.scores, _ = z.scores.delete(member)
+		z.ordered, _ = z.ordered.delete(ScoredMember{Member: member, Score: score})
+		z.size -= len(member) + scoreSize
+		removed++
+	}
+	return z, removed
+}
+
+// Pop returns sorted set w/o count members w the lowest or the highest scores and popped members
+func (z ZSet) Pop(lowest bool, count int) (ZSet, []ScoredMember) {
+	count = min(count, z.Len())
+	popped := make([]ScoredMember, count)
+	for i := range popped {
+		rank := 0
+		if !lowest {
+			rank = z.Len() - 1
+		}
+		popped[i], _ = z.ordered.at(rank)
+		z, _ = z.Remove(popped[i].Member)
+	}
+	return z, popped
+}
+
+// set score of member
+func (z ZSet) set(member string, score float64) ZSet {
+	if z.scores.less == nil {
+		z.scores = newTreap[string, float64](lessString)
+		z.ordered = newTreap[ScoredMember, struct{}](lessScoredMember)
+	}
+
+	if current, ok := z.scores.get(member); ok {
+		z.ordered, _ = z.ordered.delete(ScoredMember{Member: member, Score: current})
+	} else {
+		z.size += len(member) + scoreSize
+	}
+	z.scores = z.scores.set(member, score)
+	z.ordered = z.ordered.set(ScoredMember{Member: member, Score: score}, struct{}{})
+	return z
+}
+
+func lessScoredMember(a, b ScoredMember) bool {
+	if a.Score != b.Score {
+		return a.Score < b.Score
+	}
+	return a.Member < b.Member
+}
+
+// Validate checks that options don't conflict
+func (o ZAddOptions) Validate() error {
+	switch {
+	case o.NX && o.XX:
+		return errors.ErrConflictFields("nx", "xx")
+	case o.GT && o.LT:
+		return errors.ErrConflictFields("gt", "lt")
+	case o.NX && (o.GT || o.LT):
+		return errors.ErrConflictFields("nx", "gt or lt")
+	default:
+		return nil
+	}
+}
+
+// ParseScoreBound parses Redis-like bound: number, "-inf", "+inf", "(" prefix for exclusive bound
+func ParseScoreBound(bound string) (ScoreBound, error) {
+	parsed := ScoreBound{}
+	number := bound
+	if strings.HasPrefix(bound, "(") {
+		parsed.Exclusive, number = true, bound[1:]
+	}
+
+	score, err := strconv.ParseFloat(number, 64)
+	if err != nil || math.IsNaN(score) {
+		return ScoreBound{}, errors.ErrInvalidScoreBound(bound)
+	}
+	parsed.Score = score
+	return parsed, nil
+}
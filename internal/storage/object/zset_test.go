@@ -0,0 +1,155 @@
+package object
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+)
+
+func TestZSet(t *testing.T) {
+	zset, added, changed := ZSet{}.Add([]ScoredMember{{"b", 2}, {"a", 1}, {"c", 2}}, ZAddOptions{})
+	assert.Equal(t, 3, added)
+	assert.Equal(t, 3, changed)
+	assert.Equal(t, 3, zset.Len())
+	assert.Equal(t, 3+3*scoreSize, zset.Size())
+	assert.Equal(t, []ScoredMember{{"a", 1}, {"b", 2}, {"c", 2}}, zset.Range(0, -1))
+
+	rank, ok := zset.Rank("c")
+	assert.True(t, ok)
+	assert.Equal(t, 2, rank)
+	_, ok = zset.Rank("unknown")
+	assert.False(t, ok)
+
+	incremented, score, err := zset.Incr("a", 5)
+	require.Nil(t, err)
+	assert.Equal(t, float64(6), score)
+	assert.Equal(t, []ScoredMember{{"b", 2}, {"c", 2}, {"a", 6}}, incremented.Range(0, -1))
+	_, _, err = zset.Incr("a", math.Inf(1))
+	assert.Equal(t, errors.ErrIncrementNaN, err)
+
+	// source sorted set isn't changed
+	score, _ = zset.Score("a")
+	assert.Equal(t, float64(1), score)
+
+	removed, count := zset.Remove("b", "unknown")
+	assert.Equal(t, 1, count)
+	assert.Equal(t, []ScoredMember{{"a", 1}, {"c", 2}}, removed.Range(0, -1))
+	assert.Equal(t, 2+2*scoreSize, removed.Size())
+
+	popped, members := zset.Pop(false, 2)
+	assert.Equal(t, []ScoredMember{{"c", 2}, {"b", 2}}, members)
+	assert.Equal(t, []ScoredMember{{"a", 1}}, popped.Range(0, -1))
+	popped, members = zset.Pop(true, 5)
+	assert.Len(t, members, 3)
+	assert.Equal(t, 0, popped.Len())
+
+	data, err := removed.MarshalJSON()
+	require.Nil(t, err)
+	assert.JSONEq(t, `[{"member":"a","score":1},{"member":"c","score":2}]`, string(data))
+}
+
+func TestZSet_AddOptions(t *testing.T) {
+	zset, _, _ := ZSet{}.Add([]ScoredMember{{"a", 1}, {"b", 5}}, ZAddOptions{})
+	update := []ScoredMember{{"a", 3}, {"b", 3}, {"c", 3}}
+
+	tests := []struct {
+		name        string
+		options     ZAddOptions
+		wantAdded   int
+		wantChanged int
+		want        []ScoredMember
+	}{
+		{
+			name:        "without options",
+			wantAdded:   1,
+			wantChanged: 3,
+			want:        []ScoredMember{{"a", 3}, {"b", 3}, {"c", 3}},
+		},
+		{
+			name:        "nx",
+			options:     ZAddOptions{NX: true},
+			wantAdded:   1,
+			wantChanged: 1,
+			want:        []ScoredMember{{"a", 1}, {"c", 3}, {"b", 5}},
+		},
+		{
+			name:        "xx",
+			options:     ZAddOptions{XX: true},
+			wantChanged: 2,
+			want:        []ScoredMember{{"a", 3}, {"b", 3}},
+		},
+		{
+			name:        "gt",
+			options:     ZAddOptions{GT: true},
+			wantAdded:   1,
+			wantChanged: 2,
+			want:        []ScoredMember{{"a", 3}, {"c", 3}, {"b", 5}},
+		},
+		{
+			name:        "xx lt",
+			options:     ZAddOptions{XX: true, LT: true},
+			wantChanged: 1,
+			want:        []ScoredMember{{"a", 1}, {"b", 3}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			updated, added, changed := zset.Add(update, test.options)
+			assert.Equal(t, test.wantAdded, added)
+			assert.Equal(t, test.wantChanged, changed)
+			assert.Equal(t, test.want, updated.Range(0, -1))
+		})
+	}
+
+	_, _, changed := zset.Add([]ScoredMember{{"a", 1}}, ZAddOptions{})
+	assert.Equal(t, 0, changed)
+
+	assert.Equal(t, errors.ErrConflictFields("nx", "xx"), ZAddOptions{NX: true, XX: true}.Validate())
+	assert.Equal(t, errors.ErrConflictFields("gt", "lt"), ZAddOptions{GT: true, LT: true}.Validate())
+	assert.Equal(t, errors.ErrConflictFields("nx", "gt or lt"), ZAddOptions{NX: true, LT: true}.Validate())
+	assert.Nil(t, ZAddOptions{XX: true, GT: true}.Validate())
+}
+
+func TestZSet_RangeByScore(t *testing.T) {
+	zset, _, _ := ZSet{}.Add([]ScoredMember{{"a", 1}, {"b", 2}, {"c", 2}, {"d", 3}, {"e", 4}}, ZAddOptions{})
+
+	tests := []struct {
+		name     string
+		min, max string
+		count    int
+		want     []string
+	}{
+		{name: "all", min: "-inf", max: "+inf", want: []string{"a", "b", "c", "d", "e"}},
+		{name: "inclusive", min: "2", max: "3", want: []string{"b", "c", "d"}},
+		{name: "exclusive min", min: "(2", max: "3", want: []string{"d"}},
+		{name: "exclusive max", min: "1", max: "(2", want: []string{"a"}},
+		{name: "count", min: "2", max: "inf", count: 2, want: []string{"b", "c"}},
+		{name: "empty", min: "5", max: "10"},
+		{name: "min greater than max", min: "3", max: "1"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			min, err := ParseScoreBound(test.min)
+			require.Nil(t, err)
+			max, err := ParseScoreBound(test.max)
+			require.Nil(t, err)
+
+			var members []string
+			for _, member := range zset.RangeByScore(min, max, test.count) {
+				members = append(members, member.Member)
+			}
+			assert.Equal(t, test.want, members)
+		})
+	}
+
+	_, err := ParseScoreBound("(nan")
+	assert.Equal(t, errors.ErrInvalidScoreBound("(nan"), err)
+	_, err = ParseScoreBound("")
+	assert.Equal(t, errors.ErrInvalidScoreBound(""), err)
+}
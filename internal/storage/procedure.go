@@ -0,0 +1,446 @@
+package storage
+
+import (
+	"bytes"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+// operations of procedure steps
+const (
+	// reads data of string object into variable, missing object is nil
+	ProcedureGet = "get"
+	// writes data of string object, existing object keeps its expiration
+	ProcedureSet    = "set"
+	ProcedureDelete = "delete"
+	// increments integer object by value and writes new value into variable
+	ProcedureIncr = "incr"
+	// pushes value to the head or the tail of list
+	ProcedureLPush = "lpush"
+	ProcedureRPush = "rpush"
+	// pops value from the head or the tail of list into variable, nil for empty list
+	ProcedureLPop = "lpop"
+	ProcedureRPop = "rpop"
+	// goes to step if variable compared w value is true
+	ProcedureIf = "if"
+	// goes to step
+	ProcedureJump = "jump"
+	// aborts procedure w message, nothing is written
+	ProcedureFail = "fail"
+	// finishes procedure w value as result
+	ProcedureReturn = "return"
+)
+
+// comparisons of if step, order of values is compared as integers, nil is 0
+const (
+	CompareEqual        = "eq"
+	CompareNotEqual     = "ne"
+	CompareLess         = "lt"
+	CompareLessEqual    = "le"
+	CompareGreater      = "gt"
+	CompareGreaterEqual = "ge"
+)
+
+type (
+	// Procedure is stored program of steps executed atomically w keys and arguments of call,
+	// steps are executed one by one from the first, procedure is finished after the last step
+	Procedure struct {
+		// count of keys of call, steps refer to keys by index
+		Keys  int    `json:"keys"`
+		Steps []Step `json:"steps"`
+	}
+
+	Step struct {
+		Op string `json:"op"`
+		// index of key of call for operations w objects
+		Key int `json:"key"`
+		// value of write, increment, comparison or result
+		Value Operand `json:"value"`
+		// variable for read values and comparison
+		Var string `json:"var"`
+		// comparison of if step
+		Compare string `json:"compare"`
+		// index of the next step for if and jump steps, steps count finishes procedure
+		Goto int `json:"goto"`
+		// message of fail step
+		Message string `json:"message"`
+	}
+
+	// Operand is argument of call by index, variable or data
+	Operand struct {
+		Data []byte `json:"data"`
+		Arg  *int   `json:"arg"`
+		Var  string `json:"var"`
+	}
+
+	// procedures is concurrent safe registry of procedures by name
+	procedures struct {
+		byName map[string]Procedure
+		mu     *sync.RWMutex
+	}
+
+	// procedureCall is state of running procedure, writes aren't stored until procedure is finished
+	procedureCall struct {
+		procedure   Procedure
+		keys        []ObjectRef
+		collections []Collection
+		shards      []collection
+		// objects for missing keys created before locks
+		created []object.Object
+		args    [][]byte
+		vars    map[string][]byte
+		tx      transaction
+	}
+)
+
+func newProcedures() *procedures {
+	return &procedures{
+		byName: make(map[string]Procedure),
+		mu:     &sync.RWMutex{},
+	}
+}
+
+// CallProcedure executes procedure atomically and in isolation: shards of all keys are locked while procedure runs,
+// writes are stored only if procedure is finished by return or the last step and returns value of return step.
+// Procedure is aborted w/o writes if it fails, exceeds limits of steps or time, or any step returns error
+func CallProcedure(s Storage, name string, keys []ObjectRef, args [][]byte) ([]byte, error) {
+	procedure, err := s.GetProcedure(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) != procedure.Keys {
+		return nil, errors.ErrWrongKeysCount(procedure.Keys, len(keys))
+	}
+
+	call := procedureCall{
+		procedure:   procedure,
+		keys:        keys,
+		collections: make([]Collection, len(keys)),
+		shards:      make([]collection, len(keys)),
+		created:     make([]object.Object, len(keys)),
+		args:        args,
+		vars:        make(map[string][]byte),
+	}
+	for i, key := range keys {
+		collection, err := s.GetCollection(key.Collection)
+		if err != nil {
+			return nil, err
+		}
+
+		// objects are created before locks, so collection settings aren't read under locks
+		call.created[i] = newObject(s, collection, object.RequestSettings{})
+		call.collections[i] = collection
+		call.shards[i] = collection.shardOf(key.Key)
+	}
+	call.tx = newTransaction(call.shards)
+
+	maxSteps, timeout := s.procedureLimits()
+	unlock := lockShards(call.shards, true)
+	defer unlock()
+
+	result, err := call.run(maxSteps, time.Now().Add(timeout))
+	if err != nil {
+		return nil, err
+	}
+	if err := call.tx.commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Validate checks steps, so registered procedure could fail only by its data
+func (p Procedure) Validate() error {
+	switch {
+	case p.Keys < 0:
+		return errors.ErrNegativeField("keys")
+	case len(p.Steps) == 0:
+		return errors.ErrEmptyField("steps")
+	}
+
+	for i, step := range p.Steps {
+		if err := step.validate(p.Keys, len(p.Steps)); err != nil {
+			return errors.ErrInvalidStep(i, err)
+		}
+	}
+	return nil
+}
+
+func (s Step) validate(keys, steps int) error {
+	switch s.Op {
+	case ProcedureGet, ProcedureLPop, ProcedureRPop:
+		if s.Var == "" {
+			return errors.ErrEmptyField("var")
+		}
+		return s.validateKey(keys)
+	case ProcedureSet, ProcedureDelete, ProcedureIncr, ProcedureLPush, ProcedureRPush:
+		if err := s.validateKey(keys); err != nil {
+			return err
+		}
+		return s.Value.validate()
+	case ProcedureIf:
+		switch {
+		case s.Var == "":
+			return errors.ErrEmptyField("var")
+		case !validComparison(s.Compare):
+			return errors.ErrUnknownValue("compare", s.Compare)
+		}
+		if err := s.validateGoto(steps); err != nil {
+			return err
+		}
+		return s.Value.validate()
+	case ProcedureJump:
+		return s.validateGoto(steps)
+	case ProcedureFail:
+		return nil
+	case ProcedureReturn:
+		return s.Value.validate()
+	default:
+		return errors.ErrUnknownValue("op", s.Op)
+	}
+}
+
+func (s Step) validateKey(keys int) error {
+	if s.Key < 0 || s.Key >= keys {
+		return errors.ErrUnknownValue("key", strconv.Itoa(s.Key))
+	}
+	return nil
+}
+
+func (s Step) validateGoto(steps int) error {
+	if s.Goto < 0 || s.Goto > steps {
+		return errors.ErrUnknownValue("goto", strconv.Itoa(s.Goto))
+	}
+	return nil
+}
+
+func (o Operand) validate() error {
+	switch {
+	case o.Arg != nil && o.Var != "":
+		return errors.ErrConflictFields("arg", "var")
+	case o.Arg != nil && *o.Arg < 0:
+		return errors.ErrNegativeField("arg")
+	default:
+		return nil
+	}
+}
+
+func validComparison(compare string) bool {
+	switch compare {
+	case CompareEqual, CompareNotEqual, CompareLess, CompareLessEqual, CompareGreater, CompareGreaterEqual:
+		return true
+	default:
+		return false
+	}
+}
+
+// run executes steps until the last step, return or fail step, every step is checked by limits
+func (c *procedureCall) run(maxSteps int, deadline time.Time) ([]byte, error) {
+	for next, executed := 0, 0; next < len(c.procedure.Steps); executed++ {
+		switch {
+		case executed == maxSteps:
+			return nil, errors.ErrProcedureAborted(next, errors.ErrProcedureStepsLimit)
+		case time.Now().After(deadline):
+			return nil, errors.ErrProcedureAborted(next, errors.ErrProcedureTimeout)
+		}
+
+		index, step := next, c.procedure.Steps[next]
+		next++
+
+		value, err := c.operand(step.Value)
+		if err != nil {
+			return nil, errors.ErrProcedureAborted(index, err)
+		}
+
+		switch step.Op {
+		case ProcedureIf:
+			matched, err := compare(c.vars[step.Var], value, step.Compare)
+			if err != nil {
+				return nil, errors.ErrProcedureAborted(index, err)
+			}
+			if matched {
+				next = step.Goto
+			}
+		case ProcedureJump:
+			next = step.Goto
+		case ProcedureFail:
+			return nil, errors.ErrProcedureAborted(index, errors.ErrProcedureFailed(step.Message))
+		case ProcedureReturn:
+			return value, nil
+		default:
+			if err := c.update(index, step, value); err != nil {
+				return nil, errors.ErrProcedureAborted(index, err)
+			}
+		}
+	}
+	return nil, nil
+}
+
+// update applies operation of step to object of its key, object is written only to transaction of call
+func (c *procedureCall) update(index int, step Step, value []byte) error {
+	key, created := c.keys[step.Key].Key, c.created[step.Key]
+
+	var update UpdateFunc
+	switch step.Op {
+	case ProcedureGet:
+		update = func(current object.Object) (object.Object, error) {
+			if current != nil && current.Type() != object.TypeString {
+				return nil, errors.ErrWrongType
+			}
+			c.vars[step.Var] = nil
+			if current != nil {
+				c.vars[step.Var] = current.Binary()
+			}
+			return nil, SkipUpdate
+		}
+	case ProcedureSet:
+		update = setUpdate(created.WithData(value), object.RequestSettings{KeepTTL: true}, &SetResult{})
+	case ProcedureDelete:
+		update = func(current object.Object) (object.Object, error) {
+			if current == nil {
+				return nil, SkipUpdate
+			}
+			return nil, nil
+		}
+	case ProcedureIncr:
+		increment, err := strconv.ParseInt(string(value), 10, 64)
+		if err != nil {
+			return errors.ErrFieldNotInteger("value")
+		}
+		update = dataUpdate(created, object.RequestSettings{}, func(data []byte) ([]byte, error) {
+			updated, err := addInteger(data, increment, errors.ErrNotInteger(key))
+			if err == nil && step.Var != "" {
+				c.vars[step.Var] = updated
+			}
+			return updated, err
+		})
+	case ProcedureLPush, ProcedureRPush:
+		update = valueUpdate(created, object.RequestSettings{}, func(list object.List) (object.List, error) {
+			return list.Push(step.Op == ProcedureLPush, value), nil
+		})
+	case ProcedureLPop, ProcedureRPop:
+		update = valueUpdate(created, object.RequestSettings{}, func(list object.List) (object.List, error) {
+			c.vars[step.Var] = nil
+			if list.Len() == 0 {
+				return list, SkipUpdate
+			}
+
+			list, popped := list.Pop(step.Op == ProcedureLPop, 1)
+			c.vars[step.Var] = popped[0]
+			return list, nil
+		})
+	}
+
+	shard := c.shards[step.Key]
+	updated, err := update(c.tx.get(shard, key))
+	if err == SkipUpdate {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if updated != nil {
+		// version is set on commit
+		updated = updated.WithVersion(0)
+	}
+	c.tx.put(c.collections[step.Key], key, updated, index)
+	return nil
+}
+
+// operand returns argument of call, value of variable or data of operand
+func (c *procedureCall) operand(operand Operand) ([]byte, error) {
+	switch {
+	case operand.Arg != nil:
+		if *operand.Arg >= len(c.args) {
+			return nil, errors.ErrNoArgument(*operand.Arg)
+		}
+		return c.args[*operand.Arg], nil
+	case operand.Var != "":
+		return c.vars[operand.Var], nil
+	default:
+		return operand.Data, nil
+	}
+}
+
+// compare returns result of comparison of value w other one, order is compared for integers only
+func compare(value, other []byte, comparison string) (bool, error) {
+	switch comparison {
+	case CompareEqual:
+		return bytes.Equal(value, other), nil
+	case CompareNotEqual:
+		return !bytes.Equal(value, other), nil
+	}
+
+	first, err := parseInteger(value, "var")
+	if err != nil {
+		return false, err
+	}
+	second, err := parseInteger(other, "value")
+	if err != nil {
+		return false, err
+	}
+
+	switch comparison {
+	case CompareLess:
+		return first < second, nil
+	case CompareLessEqual:
+		return first <= second, nil
+	case CompareGreater:
+		return first > second, nil
+	default:
+		return first >= second, nil
+	}
+}
+
+// parseInteger parses integer data, nil is 0 like missing counter
+func parseInteger(data []byte, field string) (int64, error) {
+	if data == nil {
+		return 0, nil
+	}
+
+	value, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return 0, errors.ErrFieldNotInteger(field)
+	}
+	return value, nil
+}
+
+func (p *procedures) set(name string, procedure Procedure) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.byName[name] = procedure
+}
+
+func (p *procedures) get(name string) (Procedure, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	procedure, ok := p.byName[name]
+	return procedure, ok
+}
+
+func (p *procedures) delete(name string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	_, ok := p.byName[name]
+	delete(p.byName, name)
+	return ok
+}
+
+// all returns snapshot of procedures
+func (p *procedures) all() map[string]Procedure {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	all := make(map[string]Procedure, len(p.byName))
+	for name, procedure := range p.byName {
+		all[name] = procedure
+	}
+	return all
+}
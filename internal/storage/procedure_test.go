@@ -0,0 +1,255 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+var (
+	// decrementStock decrements stock if it's above zero, else fails
+	decrementStock = Procedure{
+		Keys: 1,
+		Steps: []Step{
+			{Op: ProcedureGet, Key: 0, Var: "stock"},
+			{Op: ProcedureIf, Var: "stock", Compare: CompareGreater, Value: Operand{Data: []byte("0")}, Goto: 3},
+			{Op: ProcedureFail, Message: "out of stock"},
+			{Op: ProcedureIncr, Key: 0, Value: Operand{Data: []byte("-1")}, Var: "stock"},
+			{Op: ProcedureReturn, Value: Operand{Var: "stock"}},
+		},
+	}
+
+	// moveItem moves item from the head of pending list to the tail of done list, returns nil if pending is empty
+	moveItem = Procedure{
+		Keys: 2,
+		Steps: []Step{
+			{Op: ProcedureLPop, Key: 0, Var: "item"},
+			{Op: ProcedureIf, Var: "item", Compare: CompareEqual, Goto: 4},
+			{Op: ProcedureRPush, Key: 1, Value: Operand{Var: "item"}},
+			{Op: ProcedureReturn, Value: Operand{Var: "item"}},
+		},
+	}
+)
+
+func intArg(index int) *int {
+	return &index
+}
+
+func TestCallProcedure(t *testing.T) {
+	testStorage := New(testConfig)
+	require.Nil(t, testStorage.RegisterProcedure("decrement", decrementStock))
+	require.Nil(t, testStorage.RegisterProcedure("move", moveItem))
+	require.Nil(t, setObject(testStorage, testCollection, "stock", object.RequestSettings{Data: []byte("2"), Timeless: true}))
+
+	stock := []ObjectRef{{Key: "stock"}}
+	for _, expected := range []string{"1", "0"} {
+		result, err := CallProcedure(testStorage, "decrement", stock, nil)
+		require.Nil(t, err)
+		assert.Equal(t, []byte(expected), result)
+	}
+
+	_, err := CallProcedure(testStorage, "decrement", stock, nil)
+	assert.EqualError(t, err, errors.ErrProcedureAborted(2, errors.ErrProcedureFailed("out of stock")).Error())
+	obj, err := GetObject(testStorage, testCollection, "stock")
+	require.Nil(t, err)
+	assert.Equal(t, []byte("0"), obj.Binary())
+	assert.True(t, obj.IsTimeless())
+
+	_, err = ListPush(testStorage, testCollection, "pending", false, [][]byte{[]byte("a"), []byte("b")}, object.RequestSettings{})
+	require.Nil(t, err)
+	lists := []ObjectRef{{Key: "pending"}, {Key: "done"}}
+	for _, expected := range []string{"a", "b", ""} {
+		result, err := CallProcedure(testStorage, "move", lists, nil)
+		require.Nil(t, err)
+		assert.Equal(t, expected, string(result))
+	}
+
+	done, err := ListRange(testStorage, testCollection, "done", 0, -1)
+	require.Nil(t, err)
+	assert.Equal(t, [][]byte{[]byte("a"), []byte("b")}, done)
+	length, err := ListLen(testStorage, testCollection, "pending")
+	require.Nil(t, err)
+	assert.Equal(t, 0, length)
+}
+
+func TestCallProcedure_Abort(t *testing.T) {
+	storageConfig := testConfig
+	storageConfig.Procedures.MaxSteps = 10
+	testStorage := New(storageConfig)
+	require.Nil(t, setObject(testStorage, testCollection, "name", object.RequestSettings{Data: []byte("name"), Timeless: true}))
+	version := objectVersion(t, testStorage, "name")
+
+	testCases := []struct {
+		name  string
+		steps []Step
+		args  [][]byte
+		err   error
+	}{
+		{
+			name: "steps limit",
+			steps: []Step{
+				{Op: ProcedureSet, Key: 0, Value: Operand{Data: []byte("changed")}},
+				{Op: ProcedureJump, Goto: 0},
+			},
+			err: errors.ErrProcedureAborted(0, errors.ErrProcedureStepsLimit),
+		},
+		{
+			name: "not integer",
+			steps: []Step{
+				{Op: ProcedureDelete, Key: 0},
+				{Op: ProcedureSet, Key: 0, Value: Operand{Arg: intArg(0)}},
+				{Op: ProcedureIncr, Key: 0, Value: Operand{Data: []byte("1")}},
+			},
+			args: [][]byte{[]byte("changed")},
+			err:  errors.ErrProcedureAborted(2, errors.ErrNotInteger("name")),
+		},
+		{
+			name: "wrong type",
+			steps: []Step{
+				{Op: ProcedureSet, Key: 0, Value: Operand{Data: []byte("changed")}},
+				{Op: ProcedureLPop, Key: 0, Var: "item"},
+			},
+			err: errors.ErrProcedureAborted(1, errors.ErrWrongType),
+		},
+		{
+			name: "no argument",
+			steps: []Step{
+				{Op: ProcedureDelete, Key: 0},
+				{Op: ProcedureReturn, Value: Operand{Arg: intArg(1)}},
+			},
+			args: [][]byte{[]byte("1")},
+			err:  errors.ErrProcedureAborted(1, errors.ErrNoArgument(1)),
+		},
+		{
+			name: "comparison of not integer",
+			steps: []Step{
+				{Op: ProcedureGet, Key: 0, Var: "name"},
+				{Op: ProcedureDelete, Key: 0},
+				{Op: ProcedureIf, Var: "name", Compare: CompareLess, Value: Operand{Data: []byte("1")}},
+			},
+			err: errors.ErrProcedureAborted(2, errors.ErrFieldNotInteger("var")),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Nil(t, testStorage.RegisterProcedure(tc.name, Procedure{Keys: 1, Steps: tc.steps}))
+
+			_, err := CallProcedure(testStorage, tc.name, []ObjectRef{{Key: "name"}}, tc.args)
+			assert.EqualError(t, err, tc.err.Error())
+
+			obj, err := GetObject(testStorage, testCollection, "name")
+			require.Nil(t, err)
+			assert.Equal(t, []byte("name"), obj.Binary())
+			assert.Equal(t, version, obj.Version())
+		})
+	}
+}
+
+func TestCallProcedure_EvictionRollback(t *testing.T) {
+	testStorage := newEvictingStorage(t)
+	collection, err := testStorage.GetCollection("lru")
+	require.Nil(t, err)
+	stats := collection.Stats()
+
+	// the first write evicts 3 objects, the second one is larger than memory limit
+	require.Nil(t, testStorage.RegisterProcedure("fill", Procedure{Keys: 2, Steps: []Step{
+		{Op: ProcedureSet, Key: 0, Value: Operand{Data: make([]byte, 60)}},
+		{Op: ProcedureSet, Key: 1, Value: Operand{Data: make([]byte, 200)}},
+	}}))
+	_, err = CallProcedure(testStorage, "fill", []ObjectRef{{Collection: "lru", Key: "big"}, {Collection: "lru", Key: "huge"}}, nil)
+	assert.ErrorIs(t, err, errors.ErrOutOfMemory)
+
+	// evicted objects are restored
+	for _, key := range []string{"k1", "k2", "k3", "k4", "k5"} {
+		_, err := GetObject(testStorage, "lru", key)
+		assert.Nil(t, err, key)
+	}
+	_, err = GetObject(testStorage, "lru", "big")
+	assert.Equal(t, errors.ErrNoObject("big"), err)
+	assert.Equal(t, stats, collection.Stats())
+}
+
+func TestCallProcedure_Timeout(t *testing.T) {
+	storageConfig := testConfig
+	storageConfig.Procedures.MaxSteps = 1 << 40
+	storageConfig.Procedures.Timeout = 1
+	testStorage := New(storageConfig)
+	require.Nil(t, testStorage.RegisterProcedure("loop", Procedure{Steps: []Step{{Op: ProcedureJump, Goto: 0}}}))
+
+	_, err := CallProcedure(testStorage, "loop", nil, nil)
+	assert.ErrorIs(t, err, errors.ErrProcedureTimeout)
+}
+
+func TestProcedure_Validate(t *testing.T) {
+	testCases := []struct {
+		name      string
+		procedure Procedure
+		err       error
+	}{
+		{
+			name:      "valid",
+			procedure: moveItem,
+		},
+		{
+			name:      "no steps",
+			procedure: Procedure{Keys: 1},
+			err:       errors.ErrEmptyField("steps"),
+		},
+		{
+			name:      "unknown op",
+			procedure: Procedure{Steps: []Step{{Op: "eval"}}},
+			err:       errors.ErrInvalidStep(0, errors.ErrUnknownValue("op", "eval")),
+		},
+		{
+			name:      "key out of range",
+			procedure: Procedure{Keys: 1, Steps: []Step{{Op: ProcedureDelete, Key: 1}}},
+			err:       errors.ErrInvalidStep(0, errors.ErrUnknownValue("key", "1")),
+		},
+		{
+			name:      "get w/o var",
+			procedure: Procedure{Keys: 1, Steps: []Step{{Op: ProcedureGet}}},
+			err:       errors.ErrInvalidStep(0, errors.ErrEmptyField("var")),
+		},
+		{
+			name:      "unknown comparison",
+			procedure: Procedure{Steps: []Step{{Op: ProcedureIf, Var: "x", Compare: "like"}}},
+			err:       errors.ErrInvalidStep(0, errors.ErrUnknownValue("compare", "like")),
+		},
+		{
+			name:      "goto out of range",
+			procedure: Procedure{Steps: []Step{{Op: ProcedureJump, Goto: 2}}},
+			err:       errors.ErrInvalidStep(0, errors.ErrUnknownValue("goto", "2")),
+		},
+		{
+			name:      "operand w arg and var",
+			procedure: Procedure{Steps: []Step{{Op: ProcedureReturn, Value: Operand{Arg: intArg(0), Var: "x"}}}},
+			err:       errors.ErrInvalidStep(0, errors.ErrConflictFields("arg", "var")),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.err, tc.procedure.Validate())
+		})
+	}
+}
+
+func TestStorage_Procedures(t *testing.T) {
+	testStorage := New(testConfig)
+	assert.Equal(t, errors.ErrEmptyField("name"), testStorage.RegisterProcedure("", moveItem))
+	require.Nil(t, testStorage.RegisterProcedure("move", moveItem))
+	assert.Equal(t, map[string]Procedure{"move": moveItem}, testStorage.Procedures())
+
+	_, err := CallProcedure(testStorage, "move", []ObjectRef{{Key: "pending"}}, nil)
+	assert.Equal(t, errors.ErrWrongKeysCount(2, 1), err)
+
+	require.Nil(t, testStorage.DeleteProcedure("move"))
+	assert.Equal(t, errors.ErrNoProcedure("move"), testStorage.DeleteProcedure("move"))
+	_, err = CallProcedure(testStorage, "move", nil, nil)
+	assert.Equal(t, errors.ErrNoProcedure("move"), err)
+}
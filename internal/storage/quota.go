@@ -0,0 +1,65 @@
+package storage
+
+import "sync/atomic"
+
+// quota is concurrent safe counter of used resource (memory bytes, keys) w optional limit,
+// collection quota can have storage quota as parent.
+// Limit is soft: concurrent writers can exceed it by size of their objects
+type quota struct {
+	used   *atomic.Int64
+	limit  *atomic.Int64
+	parent *quota
+}
+
+// newQuota creates quota, 0 limit - unlimited
+func newQuota(limit int64, parent *quota) *quota {
+	q := &quota{
+		used:   &atomic.Int64{},
+		limit:  &atomic.Int64{},
+		parent: parent,
+	}
+	q.limit.Store(limit)
+	return q
+}
+
+// fits checks that delta fits into quota and all its parents
+func (q *quota) fits(delta int64) bool {
+	for current := q; current != nil; current = current.parent {
+		limit := current.limit.Load()
+		if limit > 0 && current.used.Load()+delta > limit {
+			return false
+		}
+	}
+	return true
+}
+
+// exceeds checks that size is larger than limit of quota or any its parent,
+// so it couldn't fit even into empty quota
+func (q *quota) exceeds(size int64) bool {
+	for current := q; current != nil; current = current.parent {
+		limit := current.limit.Load()
+		if limit > 0 && size > limit {
+			return true
+		}
+	}
+	return false
+}
+
+func (q *quota) add(delta int64) {
+	for current := q; current != nil; current = current.parent {
+		current.used.Add(delta)
+	}
+}
+
+func (q *quota) usage() int64 {
+	return q.used.Load()
+}
+
+func (q *quota) setLimit(limit int64) {
+	q.limit.Store(limit)
+}
+
+// objectSize is count of memory bytes used by object w key
+func objectSize(key string, size int) int64 {
+	return int64(len(key) + size)
+}
@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+)
+
+const registryShardsCount = 32
+
+type (
+	// registry is concurrent safe set of collections sharded by collection name
+	registry struct {
+		shards [registryShardsCount]*registryShard
+		count  *atomic.Int64
+	}
+
+	// registryShard is copy-on-write map of collections,
+	// readers never wait for writers, writers wait only each other
+	registryShard struct {
+		collections atomic.Pointer[map[string]Collection]
+		mu          *sync.Mutex
+	}
+)
+
+func newRegistry() *registry {
+	r := &registry{
+		count: &atomic.Int64{},
+	}
+
+	for i := range r.shards {
+		shard := &registryShard{
+			mu: &sync.Mutex{},
+		}
+		shard.collections.Store(&map[string]Collection{})
+		r.shards[i] = shard
+	}
+	return r
+}
+
+func (r *registry) shard(name string) *registryShard {
+	hash := fnv.New32a()
+	hash.Write([]byte(name))
+	return r.shards[hash.Sum32()%registryShardsCount]
+}
+
+func (r *registry) get(name string) (Collection, bool) {
+	collections := *r.shard(name).collections.Load()
+	collection, ok := collections[name]
+	return collection, ok
+}
+
+// add collection if registry has less than limit collections
+func (r *registry) add(name string, collection Collection, limit int) error {
+	shard := r.shard(name)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	collections := *shard.collections.Load()
+	if _, ok := collections[name]; ok {
+		return errors.ErrCollectionAlreadyExist(name)
+	}
+
+	// reserve place for collection before publishing it
+	if r.count.Add(1) > int64(limit) {
+		r.count.Add(-1)
+		return errors.ErrMaxCollectionsCount
+	}
+
+	updated := make(map[string]Collection, len(collections)+1)
+	for key, value := range collections {
+		updated[key] = value
+	}
+	updated[name] = collection
+	shard.collections.Store(&updated)
+	return nil
+}
+
+// delete returns deleted collection
+func (r *registry) delete(name string) (Collection, error) {
+	shard := r.shard(name)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	collections := *shard.collections.Load()
+	collection, ok := collections[name]
+	if !ok {
+		return nil, errors.ErrNoCollection(name)
+	}
+
+	updated := make(map[string]Collection, len(collections))
+	for key, value := range collections {
+		if key != name {
+			updated[key] = value
+		}
+	}
+	shard.collections.Store(&updated)
+	r.count.Add(-1)
+	return collection, nil
+}
+
+// snapshot of all collections at the moment
+func (r *registry) all() map[string]Collection {
+	all := make(map[string]Collection, r.count.Load())
+	for _, shard := range r.shards {
+		for name, collection := range *shard.collections.Load() {
+			all[name] = collection
+		}
+	}
+	return all
+}
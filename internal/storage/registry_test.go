@@ -0,0 +1,147 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mustthink/go-storage-like-redis/config"
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+)
+
+func TestRegistry(t *testing.T) {
+	registry := newRegistry()
+	tests := []struct {
+		name      string
+		action    func() error
+		wantError error
+	}{
+		{
+			name:   "add collection",
+			action: func() error { return registry.add("first", NewCollection(), 2) },
+		},
+		{
+			name:      "add existing collection",
+			action:    func() error { return registry.add("first", NewCollection(), 2) },
+			wantError: errors.ErrCollectionAlreadyExist("first"),
+		},
+		{
+			name:   "add second collection",
+			action: func() error { return registry.add("second", NewCollection(), 2) },
+		},
+		{
+			name:      "too many collections",
+			action:    func() error { return registry.add("third", NewCollection(), 2) },
+			wantError: errors.ErrMaxCollectionsCount,
+		},
+		{
+			name:   "delete collection",
+			action: func() error { _, err := registry.delete("second"); return err },
+		},
+		{
+			name:      "delete unknown collection",
+			action:    func() error { _, err := registry.delete("second"); return err },
+			wantError: errors.ErrNoCollection("second"),
+		},
+		{
+			name:   "add after delete",
+			action: func() error { return registry.add("third", NewCollection(), 2) },
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.action()
+			assert.Equal(t, test.wantError, err)
+		})
+	}
+
+	assert.Len(t, registry.all(), 2)
+}
+
+// run it w -race flag
+func TestStorage_ConcurrentCollections(t *testing.T) {
+	const (
+		workers    = 16
+		iterations = 200
+	)
+
+	testStorage := New(config.StorageConfig{
+		DefaultTTL:          1,
+		MaxCollectionsCount: workers + 1,
+		RefreshTime:         1000,
+		RefreshTimeout:      1,
+		MaxRefreshes:        1,
+	})
+
+	var wg sync.WaitGroup
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// refresher over registry snapshots
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for ctx.Err() == nil {
+			for _, collection := range testStorage.(*storage).collections.all() {
+				collection.Refresh(ctx)
+			}
+		}
+	}()
+
+	// object traffic in default collection
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for ctx.Err() == nil {
+			assert.Nil(t, setObject(testStorage, defaultCollection, testKey, testRequestSettings))
+			_, err := GetObject(testStorage, defaultCollection, testKey)
+			assert.Nil(t, err)
+		}
+	}()
+
+	var workersGroup sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		workersGroup.Add(1)
+		go func(name string) {
+			defer workersGroup.Done()
+			for j := 0; j < iterations; j++ {
+				assert.Nil(t, testStorage.NewCollection(name, config.CollectionConfig{}))
+				assert.Nil(t, setObject(testStorage, name, testKey, testRequestSettings))
+				_, err := GetObject(testStorage, name, testKey)
+				assert.Nil(t, err)
+				assert.Nil(t, testStorage.DeleteCollection(name))
+			}
+		}(fmt.Sprintf("collection-%d", i))
+	}
+
+	workersGroup.Wait()
+	cancel()
+	wg.Wait()
+
+	assert.Len(t, testStorage.(*storage).collections.all(), 1)
+}
+
+func TestStorage_ConcurrentMaxCollections(t *testing.T) {
+	const workers = 64
+
+	testStorage := New(config.StorageConfig{
+		DefaultTTL:          1,
+		MaxCollectionsCount: 10,
+		RefreshTime:         1000,
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			testStorage.NewCollection(name, config.CollectionConfig{})
+		}(fmt.Sprintf("collection-%d", i))
+	}
+	wg.Wait()
+
+	assert.Len(t, testStorage.(*storage).collections.all(), 10)
+}
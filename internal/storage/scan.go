@@ -0,0 +1,165 @@
+package storage
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+const (
+	// default count of keys per scan page
+	defaultScanCount = 10
+	// max count of examined keys per key of page, it bounds lock time of page w rare matches
+	scanExaminedFactor = 10
+)
+
+// Entry is object w its key
+type Entry struct {
+	Key    string
+	Object object.Object
+}
+
+// scanCursor is position of scan: shard and the last returned key of shard,
+// w/o key scan starts from the beginning of shard
+type scanCursor struct {
+	shard  int
+	after  string
+	hasKey bool
+}
+
+// ScanCollection returns page of keys matching glob pattern and cursor of the next page,
+// empty cursor starts scan and empty next cursor means scan is finished
+func ScanCollection(s Storage, collectionName, cursor string, count int, match string) ([]string, string, error) {
+	collection, err := s.GetCollection(collectionName)
+	if err != nil {
+		return nil, "", err
+	}
+
+	entries, next, err := collection.Scan(cursor, count, match)
+	if err != nil {
+		return nil, "", err
+	}
+
+	keys := make([]string, len(entries))
+	for i, entry := range entries {
+		keys[i] = entry.Key
+	}
+	return keys, next, nil
+}
+
+func (c collection) Scan(cursor string, count int, match string) ([]Entry, string, error) {
+	position, err := parseScanArgs(cursor, match, 1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	entries, last, more := c.scan(position, scanCount(count), match)
+	if !more {
+		return entries, "", nil
+	}
+	return entries, scanCursor{after: last, hasKey: true}.encode(), nil
+}
+
+// Scan walks shards one by one, page could contain keys of several shards
+func (c shardedCollection) Scan(cursor string, count int, match string) ([]Entry, string, error) {
+	position, err := parseScanArgs(cursor, match, len(c.shards))
+	if err != nil {
+		return nil, "", err
+	}
+
+	count = scanCount(count)
+	var entries []Entry
+	for ; position.shard < len(c.shards); position = (scanCursor{shard: position.shard + 1}) {
+		shardEntries, last, more := c.shards[position.shard].scan(position, count-len(entries), match)
+		entries = append(entries, shardEntries...)
+		if more {
+			next := scanCursor{shard: position.shard, after: last, hasKey: true}
+			return entries, next.encode(), nil
+		}
+		if len(entries) == count {
+			break
+		}
+	}
+
+	if position.shard+1 >= len(c.shards) {
+		return entries, "", nil
+	}
+	return entries, scanCursor{shard: position.shard + 1}.encode(), nil
+}
+
+// scan returns up to count objects after cursor in keys order and the last examined key,
+// order of keys makes scan stable while collection is changed between pages:
+// every object existing during whole scan is returned exactly once.
+// Scan seeks to cursor by index of keys and examines limited count of keys,
+// so page could be smaller than count while shard has more keys
+func (c collection) scan(position scanCursor, count int, match string) (entries []Entry, last string, more bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	examined := 0
+	c.index.AscendAfter(position.after, position.hasKey, func(key string) bool {
+		if len(entries) == count || examined == count*scanExaminedFactor {
+			more = true
+			return false
+		}
+
+		examined++
+		last = key
+		obj := c.objects[key]
+		if !obj.IsExpired() && (match == "" || matchGlob(match, key)) {
+			entries = append(entries, Entry{Key: key, Object: obj})
+		}
+		return true
+	})
+	return entries, last, more
+}
+
+func parseScanArgs(cursor, match string, shardsCount int) (scanCursor, error) {
+	if err := validateGlob(match); err != nil {
+		return scanCursor{}, err
+	}
+
+	position, err := decodeScanCursor(cursor)
+	if err != nil || position.shard >= shardsCount {
+		return scanCursor{}, errors.ErrInvalidCursor(cursor)
+	}
+	return position, nil
+}
+
+func scanCount(count int) int {
+	if count <= 0 {
+		return defaultScanCount
+	}
+	return count
+}
+
+// encode cursor as base64 of "shard:key" or "shard" w/o key,
+// keys could be binary, so cursor is encoded
+func (c scanCursor) encode() string {
+	raw := strconv.Itoa(c.shard)
+	if c.hasKey {
+		raw += ":" + c.after
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeScanCursor(cursor string) (scanCursor, error) {
+	if cursor == "" {
+		return scanCursor{}, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return scanCursor{}, err
+	}
+
+	shard, after, hasKey := strings.Cut(string(raw), ":")
+	index, err := strconv.Atoi(shard)
+	if err != nil || index < 0 {
+		return scanCursor{}, errors.ErrInvalidCursor(cursor)
+	}
+	return scanCursor{shard: index, after: after, hasKey: hasKey}, nil
+}
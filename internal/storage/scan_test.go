@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+// scanAll collects keys of all pages
+func scanAll(t *testing.T, c Collection, count int, match string) []string {
+	var (
+		all    []string
+		cursor string
+	)
+	for {
+		entries, next, err := c.Scan(cursor, count, match)
+		require.Nil(t, err)
+		assert.LessOrEqual(t, len(entries), count)
+		for _, entry := range entries {
+			all = append(all, entry.Key)
+		}
+		if next == "" {
+			return all
+		}
+		cursor = next
+	}
+}
+
+func TestCollection_Scan(t *testing.T) {
+	collections := map[string]Collection{
+		"plain":   NewCollection(),
+		"sharded": NewShardedCollection(8),
+	}
+
+	for name, c := range collections {
+		t.Run(name, func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				require.Nil(t, c.Set("user:"+strconv.Itoa(i), testObject))
+				require.Nil(t, c.Set("session:"+strconv.Itoa(i), testObject))
+			}
+			require.Nil(t, c.Set("user:expired", object.New(nil, object.WithTimeout(-1))))
+
+			assert.Len(t, scanAll(t, c, 7, ""), 200)
+			assert.ElementsMatch(t, scanAll(t, c, 1000, "user:?"), []string{
+				"user:0", "user:1", "user:2", "user:3", "user:4",
+				"user:5", "user:6", "user:7", "user:8", "user:9",
+			})
+			assert.Len(t, scanAll(t, c, 3, "session:*"), 100)
+			assert.Empty(t, scanAll(t, c, 3, "unknown:*"))
+
+			_, _, err := c.Scan("unknown", 10, "")
+			assert.Equal(t, errors.ErrInvalidCursor("unknown"), err)
+			_, _, err = c.Scan(scanCursor{shard: 100}.encode(), 10, "")
+			assert.Equal(t, errors.ErrInvalidCursor(scanCursor{shard: 100}.encode()), err)
+			_, _, err = c.Scan("", 10, "[")
+			assert.Equal(t, errors.ErrInvalidPattern("["), err)
+		})
+	}
+}
+
+func TestCollection_ScanExaminedLimit(t *testing.T) {
+	c := NewCollection()
+	for i := 0; i < 1000; i++ {
+		require.Nil(t, c.Set(strconv.Itoa(1000+i), testObject))
+	}
+
+	// rare match returns empty page w cursor after examined keys
+	entries, next, err := c.Scan("", 1, "1999")
+	require.Nil(t, err)
+	assert.Empty(t, entries)
+	assert.Equal(t, scanCursor{after: "1009", hasKey: true}.encode(), next)
+	assert.Equal(t, []string{"1999"}, scanAll(t, c, 1, "1999"))
+
+	// deleted keys leave index
+	require.Nil(t, c.Delete("1000"))
+	entries, _, err = c.Scan("", 1, "")
+	require.Nil(t, err)
+	assert.Equal(t, "1001", entries[0].Key)
+}
+
+func TestCollection_ScanWhileWriting(t *testing.T) {
+	c := NewShardedCollection(8)
+	stable := make(map[string]bool)
+	for i := 0; i < 500; i++ {
+		key := "stable:" + strconv.Itoa(i)
+		stable[key] = true
+		require.Nil(t, c.Set(key, testObject))
+	}
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			key := "volatile:" + strconv.Itoa(i%100)
+			assert.Nil(t, c.Set(key, testObject))
+			c.Delete(key)
+		}
+	}()
+
+	// every object existing during whole scan is returned exactly once
+	seen := make(map[string]int)
+	for _, key := range scanAll(t, c, 13, "") {
+		seen[key]++
+	}
+	close(done)
+	wg.Wait()
+
+	for key := range stable {
+		assert.Equal(t, 1, seen[key], key)
+	}
+	for key, count := range seen {
+		assert.Equal(t, 1, count, key)
+	}
+}
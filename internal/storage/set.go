@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+// SetAdd adds members to set and returns count of added members,
+// missing set is created w expiration settings
+func SetAdd(s Storage, collectionName, objectKey string, members []string, expiration object.RequestSettings) (int, error) {
+	if len(members) == 0 {
+		return 0, errors.ErrEmptyField("members")
+	}
+
+	var added int
+	_, err := updateValue(s, collectionName, objectKey, expiration, func(set object.Set) (object.Set, error) {
+		set, added = set.Add(members...)
+		if added == 0 {
+			return set, SkipUpdate
+		}
+		return set, nil
+	})
+	return added, err
+}
+
+// SetRemove removes members from set and returns count of removed members, set w/o members is deleted
+func SetRemove(s Storage, collectionName, objectKey string, members []string) (int, error) {
+	var removed int
+	_, err := updateValue(s, collectionName, objectKey, object.RequestSettings{}, func(set object.Set) (object.Set, error) {
+		set, removed = set.Remove(members...)
+		if removed == 0 {
+			return set, SkipUpdate
+		}
+		return set, nil
+	})
+	return removed, err
+}
+
+func SetIsMember(s Storage, collectionName, objectKey, member string) (bool, error) {
+	set, err := viewValue[object.Set](s, collectionName, objectKey)
+	return set.Has(member), err
+}
+
+// SetMembers returns sorted members of set
+func SetMembers(s Storage, collectionName, objectKey string) ([]string, error) {
+	set, err := viewValue[object.Set](s, collectionName, objectKey)
+	if err != nil {
+		return nil, err
+	}
+	return set.Members(), nil
+}
+
+func SetCard(s Storage, collectionName, objectKey string) (int, error) {
+	set, err := viewValue[object.Set](s, collectionName, objectKey)
+	return set.Len(), err
+}
+
+// SetPop removes count random members and returns them, set w/o members is deleted
+func SetPop(s Storage, collectionName, objectKey string, count int) ([]string, error) {
+	if count < 0 {
+		return nil, errors.ErrNegativeField("count")
+	}
+
+	var popped []string
+	_, err := updateValue(s, collectionName, objectKey, object.RequestSettings{}, func(set object.Set) (object.Set, error) {
+		if set.Len() == 0 || count == 0 {
+			return set, SkipUpdate
+		}
+
+		set, popped = set.Pop(count)
+		return set, nil
+	})
+	return popped, err
+}
+
+// SetInter returns sorted members of the first set which are in all other sets
+func SetInter(s Storage, refs []ObjectRef) ([]string, error) {
+	return combineSets(s, refs, object.Set.Inter)
+}
+
+// SetUnion returns sorted members of all sets
+func SetUnion(s Storage, refs []ObjectRef) ([]string, error) {
+	return combineSets(s, refs, object.Set.Union)
+}
+
+// SetDiff returns sorted members of the first set which aren't in any other set
+func SetDiff(s Storage, refs []ObjectRef) ([]string, error) {
+	return combineSets(s, refs, object.Set.Diff)
+}
+
+// combineSets reads all sets at one moment and combines them, missing set is empty
+func combineSets(s Storage, refs []ObjectRef, combine func(first object.Set, others ...object.Set) object.Set) ([]string, error) {
+	if len(refs) == 0 {
+		return nil, errors.ErrEmptyField("keys")
+	}
+
+	objects, err := viewObjects(s, refs)
+	if err != nil {
+		return nil, err
+	}
+
+	sets := make([]object.Set, len(objects))
+	for i, obj := range objects {
+		if obj == nil {
+			continue
+		}
+
+		set, ok := obj.Value().(object.Set)
+		if !ok {
+			return nil, errors.ErrWrongType
+		}
+		sets[i] = set
+	}
+	return combine(sets[0], sets[1:]...).Members(), nil
+}
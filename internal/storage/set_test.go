@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mustthink/go-storage-like-redis/config"
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+func TestSet(t *testing.T) {
+	testStorage := New(testConfig)
+
+	added, err := SetAdd(testStorage, testCollection, "tags", []string{"go", "redis", "go"}, object.RequestSettings{})
+	require.Nil(t, err)
+	assert.Equal(t, 2, added)
+
+	isMember, err := SetIsMember(testStorage, testCollection, "tags", "go")
+	require.Nil(t, err)
+	assert.True(t, isMember)
+
+	count, err := SetCard(testStorage, testCollection, "tags")
+	require.Nil(t, err)
+	assert.Equal(t, 2, count)
+
+	removed, err := SetRemove(testStorage, testCollection, "tags", []string{"redis", "unknown"})
+	require.Nil(t, err)
+	assert.Equal(t, 1, removed)
+
+	members, err := SetMembers(testStorage, testCollection, "tags")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"go"}, members)
+
+	// set w/o members is deleted
+	popped, err := SetPop(testStorage, testCollection, "tags", 5)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"go"}, popped)
+	assert.Equal(t, int64(0), testStorage.Stats().Memory)
+
+	require.Nil(t, setObject(testStorage, testCollection, testKey, testRequestSettings))
+	_, err = SetAdd(testStorage, testCollection, testKey, []string{"a"}, object.RequestSettings{})
+	assert.Equal(t, errors.ErrWrongType, err)
+}
+
+func TestSet_Algebra(t *testing.T) {
+	storageConfig := testConfig
+	storageConfig.MaxCollectionsCount = 2
+	testStorage := New(storageConfig)
+	require.Nil(t, testStorage.NewCollection("other", config.CollectionConfig{ShardsCount: 4}))
+
+	_, err := SetAdd(testStorage, "", "first", []string{"a", "b", "c"}, object.RequestSettings{})
+	require.Nil(t, err)
+	_, err = SetAdd(testStorage, "other", "second", []string{"b", "c", "d"}, object.RequestSettings{})
+	require.Nil(t, err)
+
+	refs := []ObjectRef{{Key: "first"}, {Collection: "other", Key: "second"}}
+	members, err := SetInter(testStorage, refs)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"b", "c"}, members)
+
+	members, err = SetUnion(testStorage, refs)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"a", "b", "c", "d"}, members)
+
+	members, err = SetDiff(testStorage, refs)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"a"}, members)
+
+	// missing set is empty
+	members, err = SetInter(testStorage, append(refs, ObjectRef{Key: "unknown"}))
+	require.Nil(t, err)
+	assert.Empty(t, members)
+
+	require.Nil(t, setObject(testStorage, testCollection, testKey, testRequestSettings))
+	_, err = SetUnion(testStorage, append(refs, ObjectRef{Key: testKey}))
+	assert.Equal(t, errors.ErrWrongType, err)
+	_, err = SetUnion(testStorage, []ObjectRef{{Collection: "unknown", Key: "first"}})
+	assert.Equal(t, errors.ErrNoCollection("unknown"), err)
+}
@@ -0,0 +1,135 @@
+package storage
+
+import (
+	"context"
+	"hash/fnv"
+	"math/rand"
+
+	"github.com/mustthink/go-storage-like-redis/config"
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+// shardedCollection is implementation of Collection w lock striping,
+// keys are spread across independently locked shards by key hash
+type shardedCollection struct {
+	shards []collection
+}
+
+func NewShardedCollection(shardsCount int, opts ...CollectionOpt) Collection {
+	if shardsCount < 2 {
+		return NewCollection(opts...)
+	}
+
+	// counters and limits are common for all shards
+	opts = append([]CollectionOpt{withCounters(newCounters(nil, nil))}, opts...)
+	shards := make([]collection, shardsCount)
+	for i := range shards {
+		shards[i] = NewCollection(opts...).(collection)
+	}
+	return shardedCollection{
+		shards: shards,
+	}
+}
+
+func (c shardedCollection) shard(key string) collection {
+	hash := fnv.New32a()
+	hash.Write([]byte(key))
+	return c.shards[hash.Sum32()%uint32(len(c.shards))]
+}
+
+func (c shardedCollection) shardOf(key string) collection {
+	return c.shard(key)
+}
+
+func (c shardedCollection) allShards() []collection {
+	return c.shards
+}
+
+func (c shardedCollection) Get(key string) (object.Object, error) {
+	return c.shard(key).Get(key)
+}
+
+func (c shardedCollection) Peek(key string) (object.Object, error) {
+	return c.shard(key).Peek(key)
+}
+
+func (c shardedCollection) Set(key string, object object.Object) error {
+	_, err := c.Update(key, replace(object))
+	return err
+}
+
+func (c shardedCollection) Update(key string, update UpdateFunc) (object.Object, error) {
+	shard := c.shard(key)
+	for {
+		obj, err := shard.Update(key, update)
+		// too large object isn't ErrOutOfMemory itself, evictions don't help it
+		if err != errors.ErrOutOfMemory && err != errors.ErrMaxKeys {
+			return obj, err
+		}
+
+		// limits are common, so shard w/o victims can free place in other shards
+		if !c.evictAny() {
+			return nil, err
+		}
+	}
+}
+
+// evictAny evicts one object from any shard starting from random one
+func (c shardedCollection) evictAny() bool {
+	start := rand.Intn(len(c.shards))
+	for i := range c.shards {
+		if c.shards[(start+i)%len(c.shards)].evict() {
+			return true
+		}
+	}
+	return false
+}
+
+func (c shardedCollection) Delete(key string) error {
+	return c.shard(key).Delete(key)
+}
+
+func (c shardedCollection) Refresh(ctx context.Context) {
+	for _, shard := range c.shards {
+		if ctx.Err() != nil {
+			return
+		}
+		shard.Refresh(ctx)
+	}
+}
+
+// SampleExpired samples random shard
+func (c shardedCollection) SampleExpired(sampleSize int) (sampled, expired int) {
+	return c.shards[rand.Intn(len(c.shards))].SampleExpired(sampleSize)
+}
+
+// Stats sums objects of shards, other counters are shared by shards
+func (c shardedCollection) Stats() CollectionStats {
+	var stats CollectionStats
+	for _, shard := range c.shards {
+		shardStats := shard.Stats()
+		stats.Objects += shardStats.Objects
+		stats.Memory = shardStats.Memory
+		stats.Evictions = shardStats.Evictions
+	}
+	return stats
+}
+
+func (c shardedCollection) Drop() {
+	for _, shard := range c.shards {
+		shard.Drop()
+	}
+}
+
+func (c shardedCollection) Settings() config.CollectionConfig {
+	settings := c.shards[0].Settings()
+	settings.ShardsCount = len(c.shards)
+	return settings
+}
+
+func (c shardedCollection) Configure(update config.CollectionUpdate) {
+	for _, shard := range c.shards {
+		shard.Configure(update)
+	}
+}
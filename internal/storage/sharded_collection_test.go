@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+func TestShardedCollection_Get(t *testing.T) {
+	collection := NewShardedCollection(8)
+	tests := []struct {
+		name       string
+		toAdd      object.Object
+		getKey     string
+		wantObject object.Object
+		wantError  error
+	}{
+		{
+			name:       "shouldn't fail",
+			toAdd:      testObject,
+			getKey:     testKey,
+			wantObject: testObject.WithVersion(1),
+		},
+		{
+			name:      "no object",
+			toAdd:     testObject,
+			getKey:    "unknown",
+			wantError: errors.ErrNoObject("unknown"),
+		},
+		{
+			name:      "object expired",
+			toAdd:     object.New([]byte("1"), object.WithTimeout(-time.Second)),
+			getKey:    testKey,
+			wantError: errors.ErrNoObject(testKey),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			collection.Set(testKey, test.toAdd)
+
+			obj, err := collection.Get(test.getKey)
+			assert.Equal(t, err, test.wantError)
+			assert.Equal(t, obj, test.wantObject)
+		})
+	}
+}
+
+func TestShardedCollection_Delete(t *testing.T) {
+	collection := NewShardedCollection(8)
+	tests := []struct {
+		name      string
+		toAdd     object.Object
+		getKey    string
+		wantError error
+	}{
+		{
+			name:   "shouldn't fail",
+			toAdd:  testObject,
+			getKey: testKey,
+		},
+		{
+			name:      "no object",
+			toAdd:     testObject,
+			getKey:    "unknown",
+			wantError: errors.ErrNoObject("unknown"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			collection.Set(testKey, test.toAdd)
+
+			err := collection.Delete(test.getKey)
+			assert.Equal(t, err, test.wantError)
+		})
+	}
+}
+
+func TestNewShardedCollection(t *testing.T) {
+	assert.IsType(t, collection{}, NewShardedCollection(0))
+	assert.IsType(t, collection{}, NewShardedCollection(1))
+	assert.IsType(t, shardedCollection{}, NewShardedCollection(2))
+}
+
+// go test -bench=Collection -run=^$ ./internal/storage
+func BenchmarkCollection(b *testing.B) {
+	const keysCount = 1024
+
+	keys := make([]string, keysCount)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+	}
+
+	collections := []struct {
+		name string
+		new  func() Collection
+	}{
+		{name: "single mutex", new: func() Collection { return NewCollection() }},
+		{name: "16 shards", new: func() Collection { return NewShardedCollection(16) }},
+		{name: "64 shards", new: func() Collection { return NewShardedCollection(64) }},
+	}
+
+	for _, goroutines := range []int{1, 8, 64} {
+		for _, c := range collections {
+			b.Run(fmt.Sprintf("%s/%d goroutines", c.name, goroutines), func(b *testing.B) {
+				collection := c.new()
+				for _, key := range keys {
+					collection.Set(key, testObject)
+				}
+
+				var wg sync.WaitGroup
+				perGoroutine := b.N/goroutines + 1
+				b.ResetTimer()
+				for g := 0; g < goroutines; g++ {
+					wg.Add(1)
+					go func(offset int) {
+						defer wg.Done()
+						for i := 0; i < perGoroutine; i++ {
+							key := keys[(offset+i)%keysCount]
+							// one write per three operations
+							switch i % 3 {
+							case 0:
+								collection.Set(key, testObject)
+							case 1:
+								collection.Get(key)
+							default:
+								collection.Delete(key)
+							}
+						}
+					}(g * keysCount / goroutines)
+				}
+				wg.Wait()
+			})
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package storage
+
+import "sync/atomic"
+
+type (
+	// Stats is snapshot of storage counters
+	Stats struct {
+		Collections      int64                      `json:"collections"`
+		Memory           int64                      `json:"memory"`
+		Expiration       ExpirationStats            `json:"expiration"`
+		CollectionsStats map[string]CollectionStats `json:"collections_stats"`
+	}
+
+	// CollectionStats is snapshot of collection counters
+	CollectionStats struct {
+		Objects   int    `json:"objects"`
+		Memory    int64  `json:"memory"`
+		Evictions uint64 `json:"evictions"`
+	}
+
+	// ExpirationStats is counters of active expiration
+	ExpirationStats struct {
+		Cycles  uint64 `json:"cycles"`
+		Sampled uint64 `json:"sampled"`
+		Expired uint64 `json:"expired"`
+	}
+
+	expirationCounters struct {
+		cycles  atomic.Uint64
+		sampled atomic.Uint64
+		expired atomic.Uint64
+	}
+)
+
+func (e *expirationCounters) stats() ExpirationStats {
+	return ExpirationStats{
+		Cycles:  e.cycles.Load(),
+		Sampled: e.sampled.Load(),
+		Expired: e.expired.Load(),
+	}
+}
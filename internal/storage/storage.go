@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"sync/atomic"
 	"time"
 
 	"github.com/mustthink/go-storage-like-redis/config"
@@ -14,83 +15,219 @@ const (
 )
 
 type Storage interface {
-	NewCollection(name string) (err error)
+	// NewCollection creates collection, empty settings fields are set by storage config
+	NewCollection(name string, settings config.CollectionConfig) (err error)
 	GetCollection(name string) (collection Collection, err error)
+	// ConfigureCollection changes collection settings by non-nil fields of update,
+	// empty eviction policy is default policy of collection from storage config
+	ConfigureCollection(name string, update config.CollectionUpdate) (err error)
 	DeleteCollection(name string) (err error)
+	Stats() Stats
+
+	// RegisterProcedure validates procedure and registers it, procedure w the same name is replaced
+	RegisterProcedure(name string, procedure Procedure) (err error)
+	GetProcedure(name string) (procedure Procedure, err error)
+	DeleteProcedure(name string) (err error)
+	// Procedures returns all registered procedures by name
+	Procedures() map[string]Procedure
 
 	refreshing()
 	defaultTimeout() time.Duration
+	procedureLimits() (maxSteps int, timeout time.Duration)
 }
 
+// GetObject returns string object, data type objects are read by own operations
 func GetObject(s Storage, collectionName, objectKey string) (object.Object, error) {
 	collection, err := s.GetCollection(collectionName)
 	if err != nil {
 		return nil, err
 	}
 
-	return collection.Get(objectKey)
+	obj, err := collection.Get(objectKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if obj.Type() != object.TypeString {
+		return nil, errors.ErrWrongType
+	}
+	return obj, nil
 }
 
-func SetObject(s Storage, collectionName, objectKey string, objSettings object.RequestSettings) error {
+// SetResult - result of SetObject
+type SetResult struct {
+	// stored object, nil if write wasn't applied
+	Object object.Object
+	// object before write, nil if it didn't exist
+	Previous object.Object
+	Applied  bool
+}
+
+// SetObject sets object and returns it w new version,
+// if objSettings has version it should match version of existing object,
+// write isn't applied if NX or XX condition isn't met
+func SetObject(s Storage, collectionName, objectKey string, objSettings object.RequestSettings) (SetResult, error) {
+	if err := objSettings.Validate(); err != nil {
+		return SetResult{}, err
+	}
+
 	collection, err := s.GetCollection(collectionName)
 	if err != nil {
-		return err
+		return SetResult{}, err
 	}
 
-	obj := objSettings.New(s.defaultTimeout())
-	collection.Set(objectKey, obj)
-	return nil
+	var result SetResult
+	obj := newObject(s, collection, objSettings)
+	stored, err := collection.Update(objectKey, setUpdate(obj, objSettings, &result))
+	if err != nil {
+		return SetResult{}, err
+	}
+
+	if result.Applied {
+		result.Object = stored
+	}
+	return result, nil
+}
+
+// setUpdate returns update func which sets obj by objSettings conditions and fills result
+func setUpdate(obj object.Object, objSettings object.RequestSettings, result *SetResult) UpdateFunc {
+	return func(current object.Object) (object.Object, error) {
+		if err := checkVersion(current, objSettings.Version); err != nil {
+			return nil, err
+		}
+
+		result.Previous = current
+		switch {
+		case objSettings.NX && current != nil, objSettings.XX && current == nil:
+			return nil, SkipUpdate
+		case objSettings.KeepTTL && current != nil:
+			result.Applied = true
+			return current.WithData(obj.Binary()), nil
+		default:
+			result.Applied = true
+			return obj, nil
+		}
+	}
 }
 
-func DeleteObject(s Storage, collectionName, objectKey string) error {
+// DeleteObject deletes object, if version isn't 0 it should match version of object
+func DeleteObject(s Storage, collectionName, objectKey string, version uint64) error {
 	collection, err := s.GetCollection(collectionName)
 	if err != nil {
 		return err
 	}
 
-	return collection.Delete(objectKey)
+	if version == 0 {
+		return collection.Delete(objectKey)
+	}
+
+	_, err = collection.Update(objectKey, func(current object.Object) (object.Object, error) {
+		return nil, checkVersion(current, version)
+	})
+	return err
+}
+
+// checkVersion checks that current object has expected version, 0 - any version,
+// object.VersionExists - any version of existing object
+func checkVersion(current object.Object, version uint64) error {
+	if version == 0 {
+		return nil
+	}
+	if current == nil || (version != object.VersionExists && current.Version() != version) {
+		return errors.ErrVersionConflict
+	}
+	return nil
+}
+
+// newObject creates object w default expiration of collection
+func newObject(s Storage, collection Collection, objSettings object.RequestSettings) object.Object {
+	settings := collection.Settings()
+	switch {
+	case objSettings.HasLifetime():
+	case settings.Timeless:
+		objSettings.Timeless = true
+	case settings.DefaultTTL > 0:
+		return objSettings.New(settings.DefaultTTL * time.Second)
+	}
+	return objSettings.New(s.defaultTimeout())
 }
 
 // storage is simple implementation of Storage
 type storage struct {
-	collections map[string]Collection
+	collections *registry
 	config      config.StorageConfig
+	memory      *quota
+	expiration  *expirationCounters
+	// the last version of objects of all collections,
+	// so version isn't repeated after key or collection is deleted and created again
+	version    *atomic.Uint64
+	procedures *procedures
 }
 
 func New(config config.StorageConfig) Storage {
-	// create default collection
-	collections := make(map[string]Collection)
-	collections[defaultCollection] = NewCollection()
-
 	storage := &storage{
-		collections: collections,
+		collections: newRegistry(),
 		config:      config,
+		memory:      newQuota(config.MaxMemory, nil),
+		expiration:  &expirationCounters{},
+		version:     &atomic.Uint64{},
+		procedures:  newProcedures(),
 	}
 
+	// create default collection
+	defaultConfig := config.CollectionConfig(defaultCollection)
+	storage.collections.add(defaultCollection, storage.newCollection(defaultConfig), config.MaxCollectionsCount)
+
 	// start refreshing storage collections
-	go storage.refreshing()
+	if config.Expiration.IsSampling() {
+		go storage.sampling()
+	} else {
+		go storage.refreshing()
+	}
 	return storage
 }
 
-func (s *storage) NewCollection(name string) error {
-	if len(s.collections) == s.config.MaxCollectionsCount {
-		return errors.ErrMaxCollectionsCount
+func (s *storage) NewCollection(name string, settings config.CollectionConfig) error {
+	collectionConfig := s.config.CollectionConfig(name).Merge(settings)
+	if err := collectionConfig.Validate(); err != nil {
+		return err
+	}
+
+	return s.collections.add(name, s.newCollection(collectionConfig), s.config.MaxCollectionsCount)
+}
+
+func (s *storage) newCollection(collectionConfig config.CollectionConfig) Collection {
+	return NewShardedCollection(
+		collectionConfig.ShardsCount,
+		withCounters(newCounters(s.memory, s.version)),
+		withConfig(collectionConfig),
+	)
+}
+
+func (s *storage) ConfigureCollection(name string, update config.CollectionUpdate) error {
+	collection, err := s.GetCollection(name)
+	if err != nil {
+		return err
 	}
 
-	if _, ok := s.collections[name]; ok {
-		return errors.ErrCollectionAlreadyExist(name)
+	if update.EvictionPolicy != nil && *update.EvictionPolicy == "" {
+		policy := s.config.CollectionConfig(name).EvictionPolicy
+		update.EvictionPolicy = &policy
+	}
+	if err := update.Apply(collection.Settings()).Validate(); err != nil {
+		return err
 	}
 
-	s.collections[name] = NewCollection()
+	collection.Configure(update)
 	return nil
 }
 
 func (s *storage) GetCollection(name string) (Collection, error) {
 	if name == "" {
-		return s.collections[defaultCollection], nil
+		name = defaultCollection
 	}
 
-	if collection, ok := s.collections[name]; ok {
+	if collection, ok := s.collections.get(name); ok {
 		return collection, nil
 	}
 
@@ -102,11 +239,13 @@ func (s *storage) DeleteCollection(name string) error {
 		return errors.ErrDeleteDefaultCollection
 	}
 
-	if _, ok := s.collections[name]; !ok {
-		return errors.ErrNoCollection(name)
+	collection, err := s.collections.delete(name)
+	if err != nil {
+		return err
 	}
 
-	delete(s.collections, name)
+	// release memory of deleted collection
+	collection.Drop()
 	return nil
 }
 
@@ -117,7 +256,7 @@ func (s *storage) refreshing() {
 	semaphore := make(chan struct{}, s.config.MaxRefreshes)
 
 	for ; ; <-ticker.C {
-		for _, collection := range s.collections {
+		for _, collection := range s.collections.all() {
 			// waiting for permit
 			semaphore <- struct{}{}
 			// got permit and start refreshing collection
@@ -126,10 +265,93 @@ func (s *storage) refreshing() {
 	}
 }
 
+// sampling is Redis-like probabilistic active expiration,
+// objects which aren't sampled are deleted lazily on Get
+func (s *storage) sampling() {
+	ticker := time.NewTicker(s.config.RefreshTime * time.Second)
+	budget := s.config.Expiration.Budget * time.Millisecond
+
+	for ; ; <-ticker.C {
+		// all collections share one time budget per cycle
+		deadline := time.Now().Add(budget)
+		for _, collection := range s.collections.all() {
+			if time.Now().After(deadline) {
+				break
+			}
+			s.sampleCollection(collection, deadline)
+		}
+	}
+}
+
+// sampleCollection repeats sampling while expired fraction is above threshold
+func (s *storage) sampleCollection(collection Collection, deadline time.Time) {
+	settings := s.config.Expiration
+	for time.Now().Before(deadline) {
+		sampled, expired := collection.SampleExpired(settings.SampleSize)
+		s.expiration.cycles.Add(1)
+		s.expiration.sampled.Add(uint64(sampled))
+		s.expiration.expired.Add(uint64(expired))
+
+		if sampled == 0 || float64(expired)/float64(sampled) <= settings.Threshold {
+			return
+		}
+	}
+}
+
+func (s *storage) Stats() Stats {
+	collections := s.collections.all()
+	collectionsStats := make(map[string]CollectionStats, len(collections))
+	for name, collection := range collections {
+		collectionsStats[name] = collection.Stats()
+	}
+
+	return Stats{
+		Collections:      s.collections.count.Load(),
+		Memory:           s.memory.usage(),
+		Expiration:       s.expiration.stats(),
+		CollectionsStats: collectionsStats,
+	}
+}
+
 func (s *storage) defaultTimeout() time.Duration {
 	return s.config.DefaultTTL * time.Second
 }
 
+func (s *storage) procedureLimits() (int, time.Duration) {
+	return s.config.Procedures.Limits()
+}
+
+func (s *storage) RegisterProcedure(name string, procedure Procedure) error {
+	if name == "" {
+		return errors.ErrEmptyField("name")
+	}
+	if err := procedure.Validate(); err != nil {
+		return err
+	}
+
+	s.procedures.set(name, procedure)
+	return nil
+}
+
+func (s *storage) GetProcedure(name string) (Procedure, error) {
+	procedure, ok := s.procedures.get(name)
+	if !ok {
+		return Procedure{}, errors.ErrNoProcedure(name)
+	}
+	return procedure, nil
+}
+
+func (s *storage) DeleteProcedure(name string) error {
+	if !s.procedures.delete(name) {
+		return errors.ErrNoProcedure(name)
+	}
+	return nil
+}
+
+func (s *storage) Procedures() map[string]Procedure {
+	return s.procedures.all()
+}
+
 // something creepy... but I explain
 // refreshAndPermitNext - safe refresh collection with cancel after timeout will be expired
 func refreshAndPermitNext(timeout time.Duration, collection Collection, semaphore chan struct{}) {
@@ -141,7 +363,8 @@ func refreshAndPermitNext(timeout time.Duration, collection Collection, semaphor
 	defer cancel()
 
 	// chan for checking: has the refreshing process been completed?
-	done := make(chan struct{})
+	// it's buffered so refreshing goroutine won't leak after timeout
+	done := make(chan struct{}, 1)
 	// start refresh collection
 	go func() {
 		collection.Refresh(ctx)
@@ -1,7 +1,9 @@
 package storage
 
 import (
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -43,7 +45,7 @@ func TestGetObject(t *testing.T) {
 			storage:    New(testConfig),
 			collection: testCollection,
 			setObject:  testRequestSettings,
-			wantObject: testObject,
+			wantObject: testObject.WithVersion(1),
 		},
 		{
 			name:       "unknown collection",
@@ -55,7 +57,7 @@ func TestGetObject(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			err := SetObject(test.storage, testCollection, testKey, test.setObject)
+			_, err := SetObject(test.storage, testCollection, testKey, test.setObject)
 			require.Nil(t, err)
 
 			getObject, err := GetObject(test.storage, test.collection, testKey)
@@ -87,8 +89,224 @@ func TestSetObject(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			err := SetObject(test.storage, test.collection, testKey, testRequestSettings)
+			_, err := SetObject(test.storage, test.collection, testKey, testRequestSettings)
 			require.Equal(t, err, test.wantError)
 		})
 	}
 }
+
+func TestStorage_CollectionSettings(t *testing.T) {
+	storageConfig := testConfig
+	storageConfig.MaxCollectionsCount = 10
+	testStorage := New(storageConfig)
+
+	tests := []struct {
+		name        string
+		settings    config.CollectionConfig
+		setObject   object.RequestSettings
+		wantExpires func(obj object.Object) bool
+		wantError   error
+	}{
+		{
+			name:      "default ttl of collection",
+			settings:  config.CollectionConfig{DefaultTTL: 1800},
+			setObject: object.RequestSettings{Data: []byte("1")},
+			wantExpires: func(obj object.Object) bool {
+				return time.Until(obj.Expires()) > 29*time.Minute
+			},
+		},
+		{
+			name:      "object timeout overrides collection ttl",
+			settings:  config.CollectionConfig{DefaultTTL: 1800},
+			setObject: object.RequestSettings{Data: []byte("1"), Timeout: 10},
+			wantExpires: func(obj object.Object) bool {
+				return time.Until(obj.Expires()) < time.Minute
+			},
+		},
+		{
+			name:        "timeless collection",
+			settings:    config.CollectionConfig{Timeless: true},
+			setObject:   object.RequestSettings{Data: []byte("1")},
+			wantExpires: object.Object.IsTimeless,
+		},
+		{
+			name:      "timeless objects are forbidden",
+			settings:  config.CollectionConfig{NoTimeless: true},
+			setObject: object.RequestSettings{Data: []byte("1"), Timeless: true},
+			wantError: errors.ErrTimelessForbidden,
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			name := fmt.Sprintf("collection-%d", i)
+			require.Nil(t, testStorage.NewCollection(name, test.settings))
+
+			err := setObject(testStorage, name, testKey, test.setObject)
+			require.Equal(t, test.wantError, err)
+			if err != nil {
+				return
+			}
+
+			obj, err := GetObject(testStorage, name, testKey)
+			require.Nil(t, err)
+			assert.True(t, test.wantExpires(obj))
+		})
+	}
+}
+
+func TestStorage_ConfigureCollection(t *testing.T) {
+	storageConfig := testConfig
+	storageConfig.MaxCollectionsCount = 2
+	testStorage := New(storageConfig)
+
+	err := testStorage.NewCollection("test", config.CollectionConfig{Timeless: true, NoTimeless: true})
+	require.Equal(t, errors.ErrConflictFields("timeless", "no_timeless"), err)
+
+	require.Nil(t, testStorage.NewCollection("test", config.CollectionConfig{ShardsCount: 4, MaxKeys: 2}))
+	require.Nil(t, setObject(testStorage, "test", "1", testRequestSettings))
+	require.Nil(t, setObject(testStorage, "test", "2", testRequestSettings))
+	require.Equal(t, errors.ErrMaxKeys, setObject(testStorage, "test", "3", testRequestSettings))
+
+	err = testStorage.ConfigureCollection("test", config.CollectionUpdate{MaxKeys: ptr(-1)})
+	require.Equal(t, errors.ErrNegativeField("max_keys"), err)
+
+	// existing objects are known by new evictor
+	require.Nil(t, testStorage.ConfigureCollection("test", config.CollectionUpdate{EvictionPolicy: ptr(config.AllKeysLRU)}))
+	require.Nil(t, setObject(testStorage, "test", "3", testRequestSettings))
+
+	collection, err := testStorage.GetCollection("test")
+	require.Nil(t, err)
+	assert.Equal(t, 2, collection.Stats().Objects)
+	assert.Equal(t, uint64(1), collection.Stats().Evictions)
+	settings := config.CollectionConfig{ShardsCount: 4, MaxKeys: 2, EvictionPolicy: config.AllKeysLRU}
+	assert.Equal(t, settings, collection.Settings())
+
+	// nil fields keep current settings
+	require.Nil(t, testStorage.ConfigureCollection("test", config.CollectionUpdate{DefaultTTL: ptr(time.Duration(5))}))
+	settings.DefaultTTL = 5
+	assert.Equal(t, settings, collection.Settings())
+
+	// zero fields reset settings, limit is turned off and eviction policy is storage default
+	require.Nil(t, testStorage.ConfigureCollection("test", config.CollectionUpdate{MaxKeys: ptr(0), EvictionPolicy: ptr("")}))
+	require.Nil(t, setObject(testStorage, "test", "4", testRequestSettings))
+	assert.Equal(t, 3, collection.Stats().Objects)
+	settings.MaxKeys, settings.EvictionPolicy = 0, storageConfig.EvictionPolicy
+	assert.Equal(t, settings, collection.Settings())
+
+	err = testStorage.ConfigureCollection("test", config.CollectionUpdate{EvictionPolicy: ptr("unknown")})
+	assert.Equal(t, errors.ErrUnknownValue("eviction_policy", "unknown"), err)
+}
+
+// ptr returns pointer to value
+func ptr[T any](value T) *T {
+	return &value
+}
+
+// setObject is SetObject w/o result object
+func setObject(s Storage, collectionName, objectKey string, objSettings object.RequestSettings) error {
+	_, err := SetObject(s, collectionName, objectKey, objSettings)
+	return err
+}
+
+func TestObjectVersions(t *testing.T) {
+	testStorage := New(testConfig)
+
+	result, err := SetObject(testStorage, testCollection, testKey, testRequestSettings)
+	require.Nil(t, err)
+	first := result.Object
+
+	settings := testRequestSettings
+	settings.Version = first.Version()
+	result, err = SetObject(testStorage, testCollection, testKey, settings)
+	require.Nil(t, err)
+	second := result.Object
+	assert.Greater(t, second.Version(), first.Version())
+
+	// version of the first object is outdated
+	_, err = SetObject(testStorage, testCollection, testKey, settings)
+	assert.Equal(t, errors.ErrVersionConflict, err)
+	err = DeleteObject(testStorage, testCollection, testKey, first.Version())
+	assert.Equal(t, errors.ErrVersionConflict, err)
+
+	obj, err := GetObject(testStorage, testCollection, testKey)
+	require.Nil(t, err)
+	assert.Equal(t, second.Version(), obj.Version())
+
+	require.Nil(t, DeleteObject(testStorage, testCollection, testKey, second.Version()))
+	err = DeleteObject(testStorage, testCollection, testKey, second.Version())
+	assert.Equal(t, errors.ErrVersionConflict, err)
+}
+
+func TestSetObject_Conditions(t *testing.T) {
+	testStorage := New(testConfig)
+
+	tests := []struct {
+		name        string
+		settings    object.RequestSettings
+		wantApplied bool
+		wantData    []byte
+		wantError   error
+	}{
+		{
+			name:     "xx w/o object",
+			settings: object.RequestSettings{Data: []byte("1"), XX: true},
+			wantData: nil,
+		},
+		{
+			name:        "nx w/o object",
+			settings:    object.RequestSettings{Data: []byte("1"), NX: true, Timeout: 100},
+			wantApplied: true,
+			wantData:    []byte("1"),
+		},
+		{
+			name:     "nx w object",
+			settings: object.RequestSettings{Data: []byte("2"), NX: true},
+			wantData: []byte("1"),
+		},
+		{
+			name:        "xx w object",
+			settings:    object.RequestSettings{Data: []byte("3"), XX: true, KeepTTL: true},
+			wantApplied: true,
+			wantData:    []byte("3"),
+		},
+		{
+			name:      "nx and xx",
+			settings:  object.RequestSettings{Data: []byte("4"), NX: true, XX: true},
+			wantData:  []byte("3"),
+			wantError: errors.ErrConflictFields("nx", "xx"),
+		},
+		{
+			name:      "keepttl w expiration",
+			settings:  object.RequestSettings{Data: []byte("4"), KeepTTL: true, Timeless: true},
+			wantData:  []byte("3"),
+			wantError: errors.ErrConflictFields("keepttl", "expiration options"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := SetObject(testStorage, testCollection, testKey, test.settings)
+			assert.Equal(t, test.wantError, err)
+			assert.Equal(t, test.wantApplied, result.Applied)
+			assert.Equal(t, test.wantApplied, result.Object != nil)
+
+			obj, _ := GetObject(testStorage, testCollection, testKey)
+			if test.wantData == nil {
+				assert.Nil(t, obj)
+				return
+			}
+			require.NotNil(t, obj)
+			assert.Equal(t, test.wantData, obj.Binary())
+		})
+	}
+
+	// previous object is returned and expiration is kept
+	before, err := GetObject(testStorage, testCollection, testKey)
+	require.Nil(t, err)
+	settings := object.RequestSettings{Data: []byte("4"), KeepTTL: true, Get: true}
+	result, err := SetObject(testStorage, testCollection, testKey, settings)
+	require.Nil(t, err)
+	assert.Equal(t, []byte("3"), result.Previous.Binary())
+	assert.Equal(t, before.Expires(), result.Object.Expires())
+}
@@ -0,0 +1,240 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+// special ids of stream commands
+const (
+	// StreamLastID is id of the last entry of stream for new group
+	StreamLastID = "$"
+	// StreamNewEntries is id for reading entries never delivered to group
+	StreamNewEntries = ">"
+)
+
+// StreamAdd appends entry to stream and returns id of entry, "*" or empty id is generated from current time,
+// positive maxLen trims the oldest entries, missing stream is created w expiration settings
+func StreamAdd(s Storage, collectionName, objectKey, id string, fields map[string][]byte, maxLen int, expiration object.RequestSettings) (string, error) {
+	if len(fields) == 0 {
+		return "", errors.ErrEmptyField("fields")
+	}
+	if maxLen < 0 {
+		return "", errors.ErrNegativeField("max_len")
+	}
+
+	var entryID object.StreamID
+	_, err := updateValue(s, collectionName, objectKey, expiration, func(stream object.Stream) (object.Stream, error) {
+		var err error
+		stream, entryID, err = stream.Add(id, fields, time.Now())
+		if err != nil {
+			return stream, err
+		}
+
+		if maxLen > 0 {
+			stream, _ = stream.TrimLen(maxLen)
+		}
+		return stream, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return entryID.String(), nil
+}
+
+// StreamRange returns up to count entries w ids from start to end inclusive, 0 count - all entries,
+// "-" and "+" are the minimal and the maximal ids
+func StreamRange(s Storage, collectionName, objectKey, start, end string, count int) ([]object.StreamEntry, error) {
+	if count < 0 {
+		return nil, errors.ErrNegativeField("count")
+	}
+	startID, err := object.ParseStreamID(start, 0)
+	if err != nil {
+		return nil, err
+	}
+	endID, err := object.ParseStreamID(end, object.MaxStreamID.Seq)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := viewValue[object.Stream](s, collectionName, objectKey)
+	if err != nil {
+		return nil, err
+	}
+	return stream.Range(startID, endID, count), nil
+}
+
+// StreamLen returns count of entries of stream
+func StreamLen(s Storage, collectionName, objectKey string) (int, error) {
+	stream, err := viewValue[object.Stream](s, collectionName, objectKey)
+	return stream.Count(), err
+}
+
+// StreamTrim removes the oldest entries over positive maxLen and entries older than positive maxAge,
+// returns count of removed entries
+func StreamTrim(s Storage, collectionName, objectKey string, maxLen int, maxAge time.Duration) (int, error) {
+	switch {
+	case maxLen < 0:
+		return 0, errors.ErrNegativeField("max_len")
+	case maxAge < 0:
+		return 0, errors.ErrNegativeField("max_age")
+	case maxLen == 0 && maxAge == 0:
+		return 0, errors.ErrEmptyField("max_len or max_age")
+	}
+
+	var removed int
+	_, err := updateValue(s, collectionName, objectKey, object.RequestSettings{}, func(stream object.Stream) (object.Stream, error) {
+		removed = 0
+		if maxLen > 0 {
+			stream, removed = stream.TrimLen(maxLen)
+		}
+		if maxAge > 0 {
+			minID := object.StreamID{Ms: uint64(max(time.Now().Add(-maxAge).UnixMilli(), 0))}
+			var count int
+			stream, count = stream.TrimBefore(minID)
+			removed += count
+		}
+
+		if removed == 0 {
+			return stream, SkipUpdate
+		}
+		return stream, nil
+	})
+	return removed, err
+}
+
+// StreamGroupCreate creates consumer group which delivers entries after id, "$" is id of the last entry,
+// missing stream is created w expiration settings
+func StreamGroupCreate(s Storage, collectionName, objectKey, group, id string, expiration object.RequestSettings) error {
+	if group == "" {
+		return errors.ErrEmptyField("group")
+	}
+
+	_, err := updateValue(s, collectionName, objectKey, expiration, func(stream object.Stream) (object.Stream, error) {
+		lastDelivered, err := streamGroupID(stream, id)
+		if err != nil {
+			return stream, err
+		}
+		return stream.CreateGroup(group, lastDelivered)
+	})
+	return err
+}
+
+// StreamReadGroup delivers up to count entries to consumer of group, 0 count - all entries,
+// ">" or empty id delivers entries never delivered to group and adds them to pending entries of consumer,
+// other id returns pending entries of consumer after id
+func StreamReadGroup(s Storage, collectionName, objectKey, group, consumer, id string, count int) ([]object.StreamEntry, error) {
+	if consumer == "" {
+		return nil, errors.ErrEmptyField("consumer")
+	}
+	if count < 0 {
+		return nil, errors.ErrNegativeField("count")
+	}
+
+	if id != "" && id != StreamNewEntries {
+		after, err := object.ParseStreamID(id, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		stream, err := viewValue[object.Stream](s, collectionName, objectKey)
+		if err != nil {
+			return nil, err
+		}
+		return stream.History(group, consumer, after, count)
+	}
+
+	var entries []object.StreamEntry
+	_, err := updateValue(s, collectionName, objectKey, object.RequestSettings{}, func(stream object.Stream) (object.Stream, error) {
+		var err error
+		stream, entries, err = stream.ReadGroup(group, consumer, count, time.Now())
+		if err == nil && len(entries) == 0 {
+			return stream, SkipUpdate
+		}
+		return stream, err
+	})
+	return entries, err
+}
+
+// StreamAck acknowledges pending entries of group and returns count of acknowledged entries
+func StreamAck(s Storage, collectionName, objectKey, group string, ids []string) (int, error) {
+	streamIDs, err := parseStreamIDs(ids)
+	if err != nil {
+		return 0, err
+	}
+
+	var acked int
+	_, err = updateValue(s, collectionName, objectKey, object.RequestSettings{}, func(stream object.Stream) (object.Stream, error) {
+		var err error
+		stream, acked, err = stream.Ack(group, streamIDs...)
+		if err == nil && acked == 0 {
+			return stream, SkipUpdate
+		}
+		return stream, err
+	})
+	return acked, err
+}
+
+// StreamPending returns up to count pending entries of group, 0 count - all entries,
+// empty consumer - entries of all consumers
+func StreamPending(s Storage, collectionName, objectKey, group, consumer string, count int) ([]object.PendingEntry, error) {
+	if count < 0 {
+		return nil, errors.ErrNegativeField("count")
+	}
+
+	stream, err := viewValue[object.Stream](s, collectionName, objectKey)
+	if err != nil {
+		return nil, err
+	}
+	return stream.Pending(group, consumer, count)
+}
+
+// StreamClaim transfers pending entries idle at least minIdle to consumer and returns claimed entries
+func StreamClaim(s Storage, collectionName, objectKey, group, consumer string, minIdle time.Duration, ids []string) ([]object.StreamEntry, error) {
+	if consumer == "" {
+		return nil, errors.ErrEmptyField("consumer")
+	}
+	if minIdle < 0 {
+		return nil, errors.ErrNegativeField("min_idle")
+	}
+	streamIDs, err := parseStreamIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []object.StreamEntry
+	_, err = updateValue(s, collectionName, objectKey, object.RequestSettings{}, func(stream object.Stream) (object.Stream, error) {
+		var err error
+		stream, entries, err = stream.Claim(group, consumer, minIdle, streamIDs, time.Now())
+		return stream, err
+	})
+	return entries, err
+}
+
+// streamGroupID returns the last delivered id of new group
+func streamGroupID(stream object.Stream, id string) (object.StreamID, error) {
+	switch id {
+	case "", StreamLastID:
+		return stream.LastID(), nil
+	default:
+		return object.ParseStreamID(id, 0)
+	}
+}
+
+func parseStreamIDs(ids []string) ([]object.StreamID, error) {
+	if len(ids) == 0 {
+		return nil, errors.ErrEmptyField("ids")
+	}
+
+	streamIDs := make([]object.StreamID, len(ids))
+	for i, id := range ids {
+		streamID, err := object.ParseStreamID(id, 0)
+		if err != nil {
+			return nil, err
+		}
+		streamIDs[i] = streamID
+	}
+	return streamIDs, nil
+}
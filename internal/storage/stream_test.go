@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+func TestStream(t *testing.T) {
+	testStorage := New(testConfig)
+	fields := map[string][]byte{"event": []byte("created")}
+
+	for _, id := range []string{"1-0", "2-0", "3-0"} {
+		added, err := StreamAdd(testStorage, testCollection, "events", id, fields, 0, object.RequestSettings{Timeout: 100})
+		require.Nil(t, err)
+		assert.Equal(t, id, added)
+	}
+
+	// generated id is greater than the last one
+	added, err := StreamAdd(testStorage, testCollection, "events", "*", fields, 3, object.RequestSettings{})
+	require.Nil(t, err)
+	id, err := object.ParseStreamID(added, 0)
+	require.Nil(t, err)
+	assert.Greater(t, id.Ms, uint64(3))
+
+	count, err := StreamLen(testStorage, testCollection, "events")
+	require.Nil(t, err)
+	assert.Equal(t, 3, count)
+
+	entries, err := StreamRange(testStorage, testCollection, "events", "-", "3", 0)
+	require.Nil(t, err)
+	assert.Equal(t, []object.StreamEntry{{ID: "2-0", Fields: fields}, {ID: "3-0", Fields: fields}}, entries)
+
+	// existing stream keeps its expiration
+	ttl, err := ObjectTTL(testStorage, testCollection, "events")
+	require.Nil(t, err)
+	assert.Greater(t, ttl.Seconds(), float64(90))
+
+	// ids of entries are times of adding
+	removed, err := StreamTrim(testStorage, testCollection, "events", 0, time.Hour)
+	require.Nil(t, err)
+	assert.Equal(t, 2, removed)
+
+	// stream w/o entries is deleted
+	time.Sleep(2 * time.Millisecond)
+	removed, err = StreamTrim(testStorage, testCollection, "events", 5, time.Millisecond)
+	require.Nil(t, err)
+	assert.Equal(t, 1, removed)
+	assert.Equal(t, int64(0), testStorage.Stats().Memory)
+
+	_, err = StreamAdd(testStorage, testCollection, "events", "", nil, 0, object.RequestSettings{})
+	assert.Equal(t, errors.ErrEmptyField("fields"), err)
+
+	require.Nil(t, setObject(testStorage, testCollection, testKey, testRequestSettings))
+	_, err = StreamAdd(testStorage, testCollection, testKey, "", fields, 0, object.RequestSettings{})
+	assert.Equal(t, errors.ErrWrongType, err)
+}
+
+func TestStream_Groups(t *testing.T) {
+	testStorage := New(testConfig)
+	fields := map[string][]byte{"event": []byte("created")}
+
+	// group of missing stream creates empty stream
+	require.Nil(t, StreamGroupCreate(testStorage, testCollection, "events", "workers", "$", object.RequestSettings{}))
+	assert.Equal(t, errors.ErrGroupExists("workers"), StreamGroupCreate(testStorage, testCollection, "events", "workers", "0", object.RequestSettings{}))
+
+	for _, id := range []string{"1-0", "2-0"} {
+		_, err := StreamAdd(testStorage, testCollection, "events", id, fields, 0, object.RequestSettings{})
+		require.Nil(t, err)
+	}
+
+	entries, err := StreamReadGroup(testStorage, testCollection, "events", "workers", "alice", ">", 0)
+	require.Nil(t, err)
+	assert.Len(t, entries, 2)
+
+	// read w/o new entries isn't write
+	version := objectVersion(t, testStorage, "events")
+	entries, err = StreamReadGroup(testStorage, testCollection, "events", "workers", "bob", "", 0)
+	require.Nil(t, err)
+	assert.Empty(t, entries)
+	assert.Equal(t, version, objectVersion(t, testStorage, "events"))
+
+	entries, err = StreamReadGroup(testStorage, testCollection, "events", "workers", "alice", "1-0", 0)
+	require.Nil(t, err)
+	assert.Equal(t, []object.StreamEntry{{ID: "2-0", Fields: fields}}, entries)
+
+	entries, err = StreamClaim(testStorage, testCollection, "events", "workers", "bob", 0, []string{"1-0"})
+	require.Nil(t, err)
+	assert.Equal(t, []object.StreamEntry{{ID: "1-0", Fields: fields}}, entries)
+
+	pending, err := StreamPending(testStorage, testCollection, "events", "workers", "bob", 0)
+	require.Nil(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, 2, pending[0].Deliveries)
+
+	acked, err := StreamAck(testStorage, testCollection, "events", "workers", []string{"1-0", "2-0"})
+	require.Nil(t, err)
+	assert.Equal(t, 2, acked)
+
+	pending, err = StreamPending(testStorage, testCollection, "events", "workers", "", 0)
+	require.Nil(t, err)
+	assert.Empty(t, pending)
+
+	_, err = StreamReadGroup(testStorage, testCollection, "events", "unknown", "alice", ">", 0)
+	assert.Equal(t, errors.ErrNoGroup("unknown"), err)
+	_, err = StreamAck(testStorage, testCollection, "events", "workers", []string{"bad"})
+	assert.Equal(t, errors.ErrInvalidStreamID("bad"), err)
+}
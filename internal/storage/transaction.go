@@ -0,0 +1,310 @@
+package storage
+
+import (
+	"math/rand"
+
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+// commands of transaction operations
+const (
+	OperationSet    = "set"
+	OperationGet    = "get"
+	OperationDelete = "delete"
+	OperationIncr   = "incr"
+)
+
+type (
+	// Operation is operation of transaction on object of ref
+	Operation struct {
+		ObjectRef
+		Command string `json:"command"`
+		// object settings for set, expiration settings of new object for incr, expected version for delete
+		Settings object.RequestSettings `json:"settings"`
+		// increment for incr
+		Increment int64 `json:"increment"`
+	}
+
+	// WatchedObject is precondition of transaction, object should have version or shouldn't exist for 0 version
+	WatchedObject struct {
+		ObjectRef
+		Version uint64 `json:"version"`
+	}
+
+	// transaction is objects written by operations of transaction which aren't stored yet
+	transaction struct {
+		writes  map[transactionKey]*transactionWrite
+		ordered []*transactionWrite
+		// ids of shards locked by caller for whole transaction
+		locked map[uint64]bool
+	}
+
+	transactionKey struct {
+		shard uint64
+		key   string
+	}
+
+	transactionWrite struct {
+		shard collection
+		// shards of collection of object, they share limits
+		shards []collection
+		key    string
+		// object after the last write, nil for deleted object
+		object object.Object
+		// the last operation which wrote object
+		operation int
+	}
+
+	// transactionUndo is object replaced, deleted or evicted by commit, rollback puts it back
+	transactionUndo struct {
+		shard   collection
+		key     string
+		object  object.Object
+		evicted bool
+	}
+)
+
+// ExecTransaction executes operations atomically and in isolation: shards of all objects are locked
+// for whole transaction and written objects are stored only if all operations succeed,
+// results are object after operation (read object for get, nil for deleted or missing object),
+// object before operation and whether write is applied, only the last write of object has version.
+// Transaction is aborted w/o operations if any watched object doesn't have watched version,
+// versions aren't repeated in storage, so any write, delete or expiration of watched object aborts transaction
+func ExecTransaction(s Storage, watched []WatchedObject, operations []Operation) ([]SetResult, error) {
+	if len(operations) == 0 {
+		return nil, errors.ErrEmptyField("operations")
+	}
+
+	watchedShards := make([]collection, 0, len(watched))
+	for _, w := range watched {
+		collection, err := s.GetCollection(w.Collection)
+		switch {
+		case err != nil && w.Version != 0:
+			// collection of watched object was deleted
+			return nil, errors.ErrWatchFailed(w.Collection, w.Key)
+		case err != nil:
+			return nil, err
+		}
+		watchedShards = append(watchedShards, collection.shardOf(w.Key))
+	}
+
+	// update funcs and objects are built before locks, so collection settings aren't read under locks
+	results := make([]SetResult, len(operations))
+	updates := make([]UpdateFunc, len(operations))
+	collections := make([]Collection, len(operations))
+	shards := make([]collection, len(operations))
+	for i, operation := range operations {
+		collection, err := s.GetCollection(operation.Collection)
+		if err != nil {
+			return nil, errors.ErrTransactionAborted(i, err)
+		}
+		collections[i] = collection
+
+		updates[i], err = operation.update(s, collection, &results[i])
+		if err != nil {
+			return nil, errors.ErrTransactionAborted(i, err)
+		}
+		shards[i] = collection.shardOf(operation.Key)
+	}
+
+	locked := append(watchedShards, shards...)
+	unlock := lockShards(locked, true)
+	defer unlock()
+
+	for i, w := range watched {
+		if !w.matches(watchedShards[i].peek(w.Key)) {
+			return nil, errors.ErrWatchFailed(w.Collection, w.Key)
+		}
+	}
+
+	tx := newTransaction(locked)
+	for i, operation := range operations {
+		current := tx.get(shards[i], operation.Key)
+		updated, err := updates[i](current)
+		if err == SkipUpdate {
+			continue
+		}
+		if err != nil {
+			return nil, errors.ErrTransactionAborted(i, err)
+		}
+
+		if updated != nil {
+			// version is set on commit
+			updated = updated.WithVersion(0)
+		}
+		tx.put(collections[i], operation.Key, updated, i)
+		results[i].Object = updated
+	}
+
+	if err := tx.commit(); err != nil {
+		return nil, err
+	}
+	for _, write := range tx.ordered {
+		if write.object != nil {
+			results[write.operation].Object = write.object
+		}
+	}
+	return results, nil
+}
+
+// update returns update func of operation which fills result
+func (o Operation) update(s Storage, collection Collection, result *SetResult) (UpdateFunc, error) {
+	switch o.Command {
+	case OperationSet:
+		if err := o.Settings.Validate(); err != nil {
+			return nil, err
+		}
+		return setUpdate(newObject(s, collection, o.Settings), o.Settings, result), nil
+	case OperationGet:
+		return func(current object.Object) (object.Object, error) {
+			if current != nil && current.Type() != object.TypeString {
+				return nil, errors.ErrWrongType
+			}
+			result.Object = current
+			return nil, SkipUpdate
+		}, nil
+	case OperationDelete:
+		return func(current object.Object) (object.Object, error) {
+			if current == nil {
+				return nil, errors.ErrNoObject(o.Key)
+			}
+			if err := checkVersion(current, o.Settings.Version); err != nil {
+				return nil, err
+			}
+			result.Previous, result.Applied = current, true
+			return nil, nil
+		}, nil
+	case OperationIncr:
+		increment := dataUpdate(newObject(s, collection, o.Settings), o.Settings, func(data []byte) ([]byte, error) {
+			return addInteger(data, o.Increment, errors.ErrNotInteger(o.Key))
+		})
+		return func(current object.Object) (object.Object, error) {
+			result.Previous, result.Applied = current, true
+			return increment(current)
+		}, nil
+	default:
+		return nil, errors.ErrUnknownCommand(o.Command)
+	}
+}
+
+// matches checks that current object has watched version, nil object matches 0 version
+func (w WatchedObject) matches(current object.Object) bool {
+	if current == nil {
+		return w.Version == 0
+	}
+	return current.Version() == w.Version
+}
+
+// newTransaction returns transaction of caller which has locked shards
+func newTransaction(locked []collection) transaction {
+	t := transaction{
+		writes: make(map[transactionKey]*transactionWrite, len(locked)),
+		locked: make(map[uint64]bool, len(locked)),
+	}
+	for _, shard := range locked {
+		t.locked[shard.id] = true
+	}
+	return t
+}
+
+// get returns object written by transaction or stored object, nil for missing or expired object
+func (t transaction) get(shard collection, key string) object.Object {
+	if write, ok := t.writes[transactionKey{shard: shard.id, key: key}]; ok {
+		return write.object
+	}
+	return shard.peek(key)
+}
+
+// put writes object of collection, shard of key should be locked
+func (t *transaction) put(collection Collection, key string, obj object.Object, operation int) {
+	shard := collection.shardOf(key)
+	txKey := transactionKey{shard: shard.id, key: key}
+	write, ok := t.writes[txKey]
+	if !ok {
+		write = &transactionWrite{shard: shard, shards: collection.allShards(), key: key}
+		t.writes[txKey] = write
+		t.ordered = append(t.ordered, write)
+	}
+	write.object, write.operation = obj, operation
+}
+
+// commit stores written objects and replaces objects of writes by stored objects w versions,
+// if object doesn't fit limits other shards of collection are evicted like in shardedCollection.Update,
+// if it still doesn't fit all stored objects and objects evicted for them are restored
+func (t transaction) commit() error {
+	var (
+		undo []transactionUndo
+		// shards locked for evictions, they are unlocked after rollback
+		evicting []collection
+	)
+	defer func() {
+		for _, shard := range evicting {
+			shard.mu.Unlock()
+		}
+	}()
+
+	for _, write := range t.ordered {
+		shard := write.shard
+		undo = append(undo, transactionUndo{shard: shard, key: write.key, object: shard.objects[write.key]})
+		if write.object == nil {
+			shard.delete(write.key)
+			continue
+		}
+
+		for {
+			stored, err := shard.setEvicting(write.key, write.object, func(key string, evicted object.Object) {
+				undo = append(undo, transactionUndo{shard: shard, key: key, object: evicted, evicted: true})
+			})
+			if err == nil {
+				write.object = stored
+				break
+			}
+
+			// limits are common, so shard w/o victims can free place in other shards
+			if err == errors.ErrOutOfMemory || err == errors.ErrMaxKeys {
+				if victimShard, victim, evicted, ok := t.evictAny(write.shards, &evicting); ok {
+					undo = append(undo, transactionUndo{shard: victimShard, key: victim, object: evicted, evicted: true})
+					continue
+				}
+			}
+			rollback(undo)
+			return errors.ErrTransactionAborted(write.operation, err)
+		}
+	}
+	return nil
+}
+
+// evictAny evicts one object from any of shards starting from random one and returns its shard, key and object,
+// shards which aren't locked by transaction are locked only if they are free, so locks out of order don't deadlock,
+// they are added to evicting and stay locked until the end of commit
+func (t transaction) evictAny(shards []collection, evicting *[]collection) (collection, string, object.Object, bool) {
+	start := rand.Intn(len(shards))
+	for i := range shards {
+		shard := shards[(start+i)%len(shards)]
+		if !t.locked[shard.id] {
+			if !shard.mu.TryLock() {
+				continue
+			}
+			t.locked[shard.id] = true
+			*evicting = append(*evicting, shard)
+		}
+
+		if victim, evicted, ok := shard.evictVictim(); ok {
+			return shard, victim, evicted, true
+		}
+	}
+	return collection{}, "", nil, false
+}
+
+// rollback puts back objects in reverse order, so every object gets its state before commit
+func rollback(undo []transactionUndo) {
+	for i := len(undo) - 1; i >= 0; i-- {
+		u := undo[i]
+		u.shard.restore(u.key, u.object)
+		if u.evicted {
+			// eviction is undone, so it isn't counted
+			u.shard.counters.evictions.Add(^uint64(0))
+		}
+	}
+}
@@ -0,0 +1,323 @@
+package storage
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mustthink/go-storage-like-redis/config"
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+func TestExecTransaction(t *testing.T) {
+	storageConfig := testConfig
+	storageConfig.MaxCollectionsCount = 2
+	testStorage := New(storageConfig)
+	require.Nil(t, testStorage.NewCollection("other", config.CollectionConfig{ShardsCount: 4}))
+	require.Nil(t, setObject(testStorage, testCollection, "stock", object.RequestSettings{Data: []byte("10"), Timeless: true}))
+	require.Nil(t, setObject(testStorage, "other", "old", testRequestSettings))
+
+	results, err := ExecTransaction(testStorage, nil, []Operation{
+		{ObjectRef: ObjectRef{Key: "stock"}, Command: OperationIncr, Increment: -3},
+		{ObjectRef: ObjectRef{Collection: "other", Key: "order"}, Command: OperationSet, Settings: object.RequestSettings{Data: []byte("3"), Timeless: true}},
+		{ObjectRef: ObjectRef{Collection: "other", Key: "order"}, Command: OperationGet},
+		{ObjectRef: ObjectRef{Collection: "other", Key: "old"}, Command: OperationDelete},
+		{ObjectRef: ObjectRef{Key: "stock"}, Command: OperationIncr, Increment: -3},
+		{ObjectRef: ObjectRef{Key: "unknown"}, Command: OperationGet},
+	})
+	require.Nil(t, err)
+	require.Len(t, results, 6)
+
+	// the first write of stock isn't stored, so it has no version
+	assert.Equal(t, []byte("7"), results[0].Object.Binary())
+	assert.Equal(t, uint64(0), results[0].Object.Version())
+	assert.Equal(t, []byte("10"), results[0].Previous.Binary())
+	assert.True(t, results[0].Applied)
+	assert.Equal(t, []byte("3"), results[2].Object.Binary())
+	assert.True(t, results[3].Applied)
+	assert.Nil(t, results[3].Object)
+	assert.Equal(t, []byte("4"), results[4].Object.Binary())
+	assert.Nil(t, results[5].Object)
+
+	stock, err := GetObject(testStorage, testCollection, "stock")
+	require.Nil(t, err)
+	assert.Equal(t, []byte("4"), stock.Binary())
+	assert.Equal(t, stock.Version(), results[4].Object.Version())
+	assert.True(t, stock.IsTimeless())
+
+	order, err := GetObject(testStorage, "other", "order")
+	require.Nil(t, err)
+	assert.Equal(t, order.Version(), results[1].Object.Version())
+
+	_, err = GetObject(testStorage, "other", "old")
+	assert.Equal(t, errors.ErrNoObject("old"), err)
+}
+
+func TestExecTransaction_Rollback(t *testing.T) {
+	testStorage := New(testConfig)
+	require.Nil(t, setObject(testStorage, testCollection, "stock", object.RequestSettings{Data: []byte("10"), Timeless: true}))
+	require.Nil(t, setObject(testStorage, testCollection, "name", object.RequestSettings{Data: []byte("name"), Timeless: true}))
+	_, err := HashSet(testStorage, testCollection, "hash", map[string][]byte{"field": []byte("1")}, object.RequestSettings{})
+	require.Nil(t, err)
+	stockVersion := objectVersion(t, testStorage, "stock")
+
+	testCases := []struct {
+		name      string
+		operation Operation
+		err       error
+	}{
+		{
+			name:      "version conflict",
+			operation: Operation{ObjectRef: ObjectRef{Key: "name"}, Command: OperationDelete, Settings: object.RequestSettings{Version: 100}},
+			err:       errors.ErrVersionConflict,
+		},
+		{
+			name:      "not integer",
+			operation: Operation{ObjectRef: ObjectRef{Key: "name"}, Command: OperationIncr, Increment: 1},
+			err:       errors.ErrNotInteger("name"),
+		},
+		{
+			name:      "wrong type",
+			operation: Operation{ObjectRef: ObjectRef{Key: "hash"}, Command: OperationGet},
+			err:       errors.ErrWrongType,
+		},
+		{
+			name:      "missing object",
+			operation: Operation{ObjectRef: ObjectRef{Key: "unknown"}, Command: OperationDelete},
+			err:       errors.ErrNoObject("unknown"),
+		},
+		{
+			name:      "unknown command",
+			operation: Operation{ObjectRef: ObjectRef{Key: "name"}, Command: "unknown"},
+			err:       errors.ErrUnknownCommand("unknown"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ExecTransaction(testStorage, nil, []Operation{
+				{ObjectRef: ObjectRef{Key: "stock"}, Command: OperationIncr, Increment: -1},
+				tc.operation,
+			})
+			assert.EqualError(t, err, errors.ErrTransactionAborted(1, tc.err).Error())
+
+			stock, err := GetObject(testStorage, testCollection, "stock")
+			require.Nil(t, err)
+			assert.Equal(t, []byte("10"), stock.Binary())
+			assert.Equal(t, stockVersion, stock.Version())
+		})
+	}
+}
+
+func TestExecTransaction_MaxMemory(t *testing.T) {
+	storageConfig := testConfig
+	storageConfig.MaxMemory = 4
+	testStorage := New(storageConfig)
+	require.Nil(t, setObject(testStorage, testCollection, "1", testRequestSettings))
+	memory := testStorage.Stats().Memory
+
+	_, err := ExecTransaction(testStorage, nil, []Operation{
+		{ObjectRef: ObjectRef{Key: "1"}, Command: OperationDelete},
+		{ObjectRef: ObjectRef{Key: "2"}, Command: OperationSet, Settings: testRequestSettings},
+		{ObjectRef: ObjectRef{Key: "3"}, Command: OperationSet, Settings: object.RequestSettings{Data: make([]byte, 10)}},
+	})
+	assert.ErrorIs(t, err, errors.ErrOutOfMemory)
+
+	// committed writes are restored
+	obj, err := GetObject(testStorage, testCollection, "1")
+	require.Nil(t, err)
+	assert.Equal(t, testObject.Binary(), obj.Binary())
+	_, err = GetObject(testStorage, testCollection, "2")
+	assert.Equal(t, errors.ErrNoObject("2"), err)
+	assert.Equal(t, memory, testStorage.Stats().Memory)
+}
+
+// newEvictingStorage returns storage w collection "lru" which evicts objects over 100 bytes and forbids timeless objects,
+// collection has 5 objects of 12 bytes
+func newEvictingStorage(t *testing.T) Storage {
+	storageConfig := testConfig
+	storageConfig.MaxCollectionsCount = 2
+	testStorage := New(storageConfig)
+	require.Nil(t, testStorage.NewCollection("lru", config.CollectionConfig{
+		ShardsCount:    1,
+		MaxMemory:      100,
+		EvictionPolicy: config.AllKeysLRU,
+		NoTimeless:     true,
+	}))
+
+	for _, key := range []string{"k1", "k2", "k3", "k4", "k5"} {
+		require.Nil(t, setObject(testStorage, "lru", key, object.RequestSettings{Data: make([]byte, 10), Timeout: 100}))
+	}
+	return testStorage
+}
+
+func TestExecTransaction_EvictionRollback(t *testing.T) {
+	testStorage := newEvictingStorage(t)
+	collection, err := testStorage.GetCollection("lru")
+	require.Nil(t, err)
+	stats := collection.Stats()
+
+	// the first write evicts 3 objects, the second one is forbidden
+	_, err = ExecTransaction(testStorage, nil, []Operation{
+		{ObjectRef: ObjectRef{Collection: "lru", Key: "big"}, Command: OperationSet, Settings: object.RequestSettings{Data: make([]byte, 60)}},
+		{ObjectRef: ObjectRef{Collection: "lru", Key: "timeless"}, Command: OperationSet, Settings: object.RequestSettings{Data: []byte("1"), Timeless: true}},
+	})
+	assert.EqualError(t, err, errors.ErrTransactionAborted(1, errors.ErrTimelessForbidden).Error())
+
+	// evicted objects are restored
+	for _, key := range []string{"k1", "k2", "k3", "k4", "k5"} {
+		_, err := GetObject(testStorage, "lru", key)
+		assert.Nil(t, err, key)
+	}
+	_, err = GetObject(testStorage, "lru", "big")
+	assert.Equal(t, errors.ErrNoObject("big"), err)
+	assert.Equal(t, stats, collection.Stats())
+}
+
+func TestExecTransaction_EvictOtherShards(t *testing.T) {
+	storageConfig := testConfig
+	storageConfig.MaxCollectionsCount = 2
+	testStorage := New(storageConfig)
+	require.Nil(t, testStorage.NewCollection("lru", config.CollectionConfig{
+		ShardsCount:    4,
+		MaxKeys:        2,
+		EvictionPolicy: config.AllKeysLRU,
+		NoTimeless:     true,
+	}))
+	collection, err := testStorage.GetCollection("lru")
+	require.Nil(t, err)
+
+	// collection is full, shard of key doesn't have victims
+	settings := object.RequestSettings{Data: []byte("1"), Timeout: 100}
+	require.Nil(t, setObject(testStorage, "lru", "k1", settings))
+	require.Nil(t, setObject(testStorage, "lru", "k2", settings))
+	key := "k3"
+	for i := 4; collection.shardOf(key).id == collection.shardOf("k1").id || collection.shardOf(key).id == collection.shardOf("k2").id; i++ {
+		key = "k" + strconv.Itoa(i)
+	}
+	stats := collection.Stats()
+
+	// the first write evicts object of other shard, the second one is forbidden, so evicted object is restored
+	_, err = ExecTransaction(testStorage, nil, []Operation{
+		{ObjectRef: ObjectRef{Collection: "lru", Key: key}, Command: OperationSet, Settings: settings},
+		{ObjectRef: ObjectRef{Collection: "lru", Key: "timeless"}, Command: OperationSet, Settings: object.RequestSettings{Data: []byte("1"), Timeless: true}},
+	})
+	assert.EqualError(t, err, errors.ErrTransactionAborted(1, errors.ErrTimelessForbidden).Error())
+	assert.Equal(t, stats, collection.Stats())
+	for _, restored := range []string{"k1", "k2"} {
+		_, err := GetObject(testStorage, "lru", restored)
+		assert.Nil(t, err, restored)
+	}
+
+	// single write fits like plain set
+	results, err := ExecTransaction(testStorage, nil, []Operation{
+		{ObjectRef: ObjectRef{Collection: "lru", Key: key}, Command: OperationSet, Settings: settings},
+	})
+	require.Nil(t, err)
+	assert.Equal(t, []byte("1"), results[0].Object.Binary())
+
+	stats = collection.Stats()
+	assert.Equal(t, 2, stats.Objects)
+	assert.Equal(t, uint64(1), stats.Evictions)
+	_, err = GetObject(testStorage, "lru", key)
+	assert.Nil(t, err)
+}
+
+func TestExecTransaction_Watch(t *testing.T) {
+	storageConfig := testConfig
+	storageConfig.MaxCollectionsCount = 2
+	testStorage := New(storageConfig)
+	require.Nil(t, setObject(testStorage, testCollection, "stock", object.RequestSettings{Data: []byte("10"), Timeless: true}))
+	decrement := []Operation{{ObjectRef: ObjectRef{Key: "stock"}, Command: OperationIncr, Increment: -1}}
+
+	testCases := []struct {
+		name string
+		// watch returns watched object and changes it
+		watch  func(t *testing.T) WatchedObject
+		failed bool
+	}{
+		{
+			name: "unchanged",
+			watch: func(t *testing.T) WatchedObject {
+				return WatchedObject{ObjectRef: ObjectRef{Key: "stock"}, Version: objectVersion(t, testStorage, "stock")}
+			},
+		},
+		{
+			name: "still missing",
+			watch: func(t *testing.T) WatchedObject {
+				return WatchedObject{ObjectRef: ObjectRef{Key: "unknown"}}
+			},
+		},
+		{
+			name: "created",
+			watch: func(t *testing.T) WatchedObject {
+				require.Nil(t, setObject(testStorage, testCollection, "created", testRequestSettings))
+				return WatchedObject{ObjectRef: ObjectRef{Key: "created"}}
+			},
+			failed: true,
+		},
+		{
+			name: "deleted and created again",
+			watch: func(t *testing.T) WatchedObject {
+				require.Nil(t, setObject(testStorage, testCollection, "recreated", testRequestSettings))
+				version := objectVersion(t, testStorage, "recreated")
+				require.Nil(t, DeleteObject(testStorage, testCollection, "recreated", 0))
+				require.Nil(t, setObject(testStorage, testCollection, "recreated", testRequestSettings))
+				return WatchedObject{ObjectRef: ObjectRef{Key: "recreated"}, Version: version}
+			},
+			failed: true,
+		},
+		{
+			name: "expired",
+			watch: func(t *testing.T) WatchedObject {
+				require.Nil(t, setObject(testStorage, testCollection, "expired", object.RequestSettings{Deadline: time.Now().Add(10 * time.Millisecond)}))
+				version := objectVersion(t, testStorage, "expired")
+				time.Sleep(20 * time.Millisecond)
+				return WatchedObject{ObjectRef: ObjectRef{Key: "expired"}, Version: version}
+			},
+			failed: true,
+		},
+		{
+			name: "collection created again",
+			watch: func(t *testing.T) WatchedObject {
+				require.Nil(t, testStorage.NewCollection("other", config.CollectionConfig{}))
+				require.Nil(t, setObject(testStorage, "other", "watched", testRequestSettings))
+				obj, err := GetObject(testStorage, "other", "watched")
+				require.Nil(t, err)
+
+				// new collection doesn't repeat versions of deleted one
+				require.Nil(t, testStorage.DeleteCollection("other"))
+				require.Nil(t, testStorage.NewCollection("other", config.CollectionConfig{}))
+				require.Nil(t, setObject(testStorage, "other", "watched", testRequestSettings))
+				return WatchedObject{ObjectRef: ObjectRef{Collection: "other", Key: "watched"}, Version: obj.Version()}
+			},
+			failed: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			watched := tc.watch(t)
+			stock := objectVersion(t, testStorage, "stock")
+
+			_, err := ExecTransaction(testStorage, []WatchedObject{watched}, decrement)
+			if !tc.failed {
+				require.Nil(t, err)
+				assert.NotEqual(t, stock, objectVersion(t, testStorage, "stock"))
+				return
+			}
+
+			assert.ErrorIs(t, err, errors.ErrWatchedObjectChanged)
+			assert.Equal(t, stock, objectVersion(t, testStorage, "stock"))
+		})
+	}
+}
+
+func TestExecTransaction_Empty(t *testing.T) {
+	_, err := ExecTransaction(New(testConfig), nil, nil)
+	assert.Equal(t, errors.ErrEmptyField("operations"), err)
+}
@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+// ObjectTTL returns remaining time to live of object, -1 for timeless object
+func ObjectTTL(s Storage, collectionName, objectKey string) (time.Duration, error) {
+	collection, err := s.GetCollection(collectionName)
+	if err != nil {
+		return 0, err
+	}
+
+	// object of any type has TTL, inspection doesn't extend sliding expiration
+	obj, err := collection.Peek(objectKey)
+	if err != nil {
+		return 0, err
+	}
+	return obj.TTL(), nil
+}
+
+// ExpireObject atomically changes expiration of existing object and returns updated object,
+// empty expiration settings set default TTL of collection,
+// object is deleted and nil is returned if new expiration time has passed
+func ExpireObject(s Storage, collectionName, objectKey string, expiration object.RequestSettings) (object.Object, error) {
+	collection, err := s.GetCollection(collectionName)
+	if err != nil {
+		return nil, err
+	}
+
+	// expiration is calculated before update, so collection settings aren't read under collection lock
+	expires := newObject(s, collection, expiration).Expires()
+	return collection.Update(objectKey, func(current object.Object) (object.Object, error) {
+		if current == nil {
+			return nil, errors.ErrNoObject(objectKey)
+		}
+
+		if !expires.After(time.Now()) {
+			return nil, nil
+		}
+		return current.WithExpires(expires), nil
+	})
+}
@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+func TestExpireObject(t *testing.T) {
+	testStorage := New(testConfig)
+	require.Nil(t, setObject(testStorage, testCollection, testKey, testRequestSettings))
+
+	tests := []struct {
+		name       string
+		key        string
+		expiration object.RequestSettings
+		wantTTL    time.Duration
+		wantError  error
+	}{
+		{
+			name:       "timeout",
+			key:        testKey,
+			expiration: object.RequestSettings{Timeout: 100},
+			wantTTL:    100 * time.Second,
+		},
+		{
+			name:       "deadline",
+			key:        testKey,
+			expiration: object.RequestSettings{Deadline: time.Now().Add(time.Hour)},
+			wantTTL:    time.Hour,
+		},
+		{
+			name:       "default ttl",
+			key:        testKey,
+			expiration: object.RequestSettings{},
+			wantTTL:    time.Second,
+		},
+		{
+			name:       "timeless",
+			key:        testKey,
+			expiration: object.RequestSettings{Timeless: true},
+			wantTTL:    -1,
+		},
+		{
+			name:       "no object",
+			key:        "unknown",
+			expiration: object.RequestSettings{Timeout: 100},
+			wantError:  errors.ErrNoObject("unknown"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ExpireObject(testStorage, testCollection, test.key, test.expiration)
+			assert.Equal(t, test.wantError, err)
+			if err != nil {
+				return
+			}
+
+			ttl, err := ObjectTTL(testStorage, testCollection, test.key)
+			require.Nil(t, err)
+			assert.InDelta(t, test.wantTTL, ttl, float64(100*time.Millisecond))
+		})
+	}
+
+	// passed expiration deletes object
+	obj, err := ExpireObject(testStorage, testCollection, testKey, object.RequestSettings{Timeout: -1})
+	require.Nil(t, err)
+	assert.Nil(t, obj)
+	_, err = GetObject(testStorage, testCollection, testKey)
+	assert.Equal(t, errors.ErrNoObject(testKey), err)
+}
+
+func TestExpireObject_ExpiryIndex(t *testing.T) {
+	testStorage := New(testConfig)
+	require.Nil(t, setObject(testStorage, testCollection, testKey, testRequestSettings))
+
+	c, err := testStorage.GetCollection(testCollection)
+	require.Nil(t, err)
+	// testConfig makes plain collection
+	expiry := c.(collection).expiry
+	assert.Equal(t, 0, expiry.len())
+
+	_, err = ExpireObject(testStorage, testCollection, testKey, object.RequestSettings{Timeout: 100})
+	require.Nil(t, err)
+	assert.Equal(t, 1, expiry.len())
+
+	_, err = ExpireObject(testStorage, testCollection, testKey, object.RequestSettings{Timeless: true})
+	require.Nil(t, err)
+	assert.Equal(t, 0, expiry.len())
+}
+
+func TestObjectTTL_Sliding(t *testing.T) {
+	// hard limit of lifetime doesn't cut sliding window
+	storageConfig := testConfig
+	storageConfig.DefaultTTL = 100
+	testStorage := New(storageConfig)
+	require.Nil(t, setObject(testStorage, testCollection, testKey, object.RequestSettings{Data: []byte("1"), Sliding: 1}))
+
+	// TTL doesn't extend sliding window, read does
+	time.Sleep(300 * time.Millisecond)
+	ttl, err := ObjectTTL(testStorage, testCollection, testKey)
+	require.Nil(t, err)
+	assert.InDelta(t, 700*time.Millisecond, ttl, float64(100*time.Millisecond))
+
+	ttl, err = ObjectTTL(testStorage, testCollection, testKey)
+	require.Nil(t, err)
+	assert.Less(t, ttl, 800*time.Millisecond)
+
+	_, err = GetObject(testStorage, testCollection, testKey)
+	require.Nil(t, err)
+	ttl, err = ObjectTTL(testStorage, testCollection, testKey)
+	require.Nil(t, err)
+	assert.Greater(t, ttl, 900*time.Millisecond)
+}
@@ -0,0 +1,155 @@
+package storage
+
+import (
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+// updateValue atomically replaces value of data type object by update func and returns updated object,
+// missing object is created w expiration settings, existing object keeps its expiration if settings are empty,
+// object w empty value is deleted, update func could return SkipUpdate if value isn't changed
+func updateValue[V object.Value](s Storage, collectionName, objectKey string, expiration object.RequestSettings, update func(value V) (V, error)) (object.Object, error) {
+	collection, err := s.GetCollection(collectionName)
+	if err != nil {
+		return nil, err
+	}
+
+	// object is created before update, so collection settings aren't read under collection lock
+	created := newObject(s, collection, expiration)
+	return collection.Update(objectKey, valueUpdate(created, expiration, update))
+}
+
+// valueUpdate returns update func which replaces value of data type object by update func,
+// created is object for missing object, existing object keeps its expiration if settings are empty
+func valueUpdate[V object.Value](created object.Object, expiration object.RequestSettings, update func(value V) (V, error)) UpdateFunc {
+	return func(current object.Object) (object.Object, error) {
+		var value V
+		if current != nil {
+			typed, ok := current.Value().(V)
+			if !ok {
+				return nil, errors.ErrWrongType
+			}
+			value = typed
+		}
+
+		updated, err := update(value)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case updated.Len() == 0:
+			return nil, nil
+		case current != nil && !expiration.HasExpiration():
+			return current.WithValue(updated), nil
+		default:
+			return created.WithValue(updated), nil
+		}
+	}
+}
+
+// viewValue returns value of data type object, missing object has empty value
+func viewValue[V object.Value](s Storage, collectionName, objectKey string) (V, error) {
+	var value V
+	collection, err := s.GetCollection(collectionName)
+	if err != nil {
+		return value, err
+	}
+
+	obj, err := collection.Get(objectKey)
+	if err != nil {
+		return value, nil
+	}
+
+	typed, ok := obj.Value().(V)
+	if !ok {
+		return value, errors.ErrWrongType
+	}
+	return typed, nil
+}
+
+// updateData atomically replaces data of string object by update func and returns updated object,
+// missing object has nil data and is created w expiration settings, update func could return SkipUpdate
+func updateData(s Storage, collectionName, objectKey string, expiration object.RequestSettings, update func(data []byte) ([]byte, error)) (object.Object, error) {
+	collection, err := s.GetCollection(collectionName)
+	if err != nil {
+		return nil, err
+	}
+
+	// object is created before update, so collection settings aren't read under collection lock
+	created := newObject(s, collection, expiration)
+	return collection.Update(objectKey, dataUpdate(created, expiration, update))
+}
+
+// dataUpdate returns update func which replaces data of string object by update func,
+// created is object for missing object, existing object keeps its expiration if settings are empty
+func dataUpdate(created object.Object, expiration object.RequestSettings, update func(data []byte) ([]byte, error)) UpdateFunc {
+	return func(current object.Object) (object.Object, error) {
+		var data []byte
+		if current != nil {
+			if current.Type() != object.TypeString {
+				return nil, errors.ErrWrongType
+			}
+			data = current.Binary()
+		}
+
+		updated, err := update(data)
+		if err != nil {
+			return nil, err
+		}
+
+		if current != nil && !expiration.HasExpiration() {
+			return current.WithData(updated), nil
+		}
+		return created.WithData(updated), nil
+	}
+}
+
+// updateBits atomically replaces bits of string object by update func,
+// missing object has empty bits and is created w expiration settings, update func could return SkipUpdate
+func updateBits(s Storage, collectionName, objectKey string, expiration object.RequestSettings, update func(bits object.Bits) (object.Bits, error)) (object.Object, error) {
+	collection, err := s.GetCollection(collectionName)
+	if err != nil {
+		return nil, err
+	}
+
+	// object is created before update, so collection settings aren't read under collection lock
+	created := newObject(s, collection, expiration)
+	return collection.Update(objectKey, func(current object.Object) (object.Object, error) {
+		var bits object.Bits
+		if current != nil {
+			if current.Type() != object.TypeString {
+				return nil, errors.ErrWrongType
+			}
+			bits = current.Bits()
+		}
+
+		updated, err := update(bits)
+		if err != nil {
+			return nil, err
+		}
+
+		if current != nil && !expiration.HasExpiration() {
+			return current.WithBits(updated), nil
+		}
+		return created.WithBits(updated), nil
+	})
+}
+
+// viewBits returns bits of string object, missing object has empty bits
+func viewBits(s Storage, collectionName, objectKey string) (object.Bits, error) {
+	collection, err := s.GetCollection(collectionName)
+	if err != nil {
+		return object.Bits{}, err
+	}
+
+	obj, err := collection.Get(objectKey)
+	if err != nil {
+		return object.Bits{}, nil
+	}
+
+	if obj.Type() != object.TypeString {
+		return object.Bits{}, errors.ErrWrongType
+	}
+	return obj.Bits(), nil
+}
@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+// ZSetAdd adds members w scores to sorted set or updates scores of existing members by options
+// and returns count of added members, missing sorted set is created w expiration settings
+func ZSetAdd(s Storage, collectionName, objectKey string, members []object.ScoredMember, options object.ZAddOptions, expiration object.RequestSettings) (int, error) {
+	if len(members) == 0 {
+		return 0, errors.ErrEmptyField("members")
+	}
+	if err := options.Validate(); err != nil {
+		return 0, err
+	}
+
+	var added int
+	_, err := updateValue(s, collectionName, objectKey, expiration, func(zset object.ZSet) (object.ZSet, error) {
+		var changed int
+		zset, added, changed = zset.Add(members, options)
+		if changed == 0 {
+			return zset, SkipUpdate
+		}
+		return zset, nil
+	})
+	return added, err
+}
+
+// ZSetIncrement increases score of member by increment and returns new score,
+// missing member is added w increment score
+func ZSetIncrement(s Storage, collectionName, objectKey, member string, increment float64, expiration object.RequestSettings) (float64, error) {
+	var score float64
+	_, err := updateValue(s, collectionName, objectKey, expiration, func(zset object.ZSet) (object.ZSet, error) {
+		var err error
+		zset, score, err = zset.Incr(member, increment)
+		return zset, err
+	})
+	return score, err
+}
+
+// ZSetScore returns score of member and whether member exists
+func ZSetScore(s Storage, collectionName, objectKey, member string) (float64, bool, error) {
+	zset, err := viewValue[object.ZSet](s, collectionName, objectKey)
+	if err != nil {
+		return 0, false, err
+	}
+	score, ok := zset.Score(member)
+	return score, ok, nil
+}
+
+// ZSetRank returns rank of member from the lowest score and whether member exists
+func ZSetRank(s Storage, collectionName, objectKey, member string) (int, bool, error) {
+	zset, err := viewValue[object.ZSet](s, collectionName, objectKey)
+	if err != nil {
+		return 0, false, err
+	}
+	rank, ok := zset.Rank(member)
+	return rank, ok, nil
+}
+
+// ZSetRange returns members w ranks from start to stop inclusive, negative rank is counted from the highest score
+func ZSetRange(s Storage, collectionName, objectKey string, start, stop int) ([]object.ScoredMember, error) {
+	zset, err := viewValue[object.ZSet](s, collectionName, objectKey)
+	if err != nil {
+		return nil, err
+	}
+	return zset.Range(start, stop), nil
+}
+
+// ZSetRangeByScore returns up to count members w scores between min and max, 0 count - all members
+func ZSetRangeByScore(s Storage, collectionName, objectKey string, min, max object.ScoreBound, count int) ([]object.ScoredMember, error) {
+	if count < 0 {
+		return nil, errors.ErrNegativeField("count")
+	}
+
+	zset, err := viewValue[object.ZSet](s, collectionName, objectKey)
+	if err != nil {
+		return nil, err
+	}
+	return zset.RangeByScore(min, max, count), nil
+}
+
+// ZSetRemove removes members from sorted set and returns count of removed members, sorted set w/o members is deleted
+func ZSetRemove(s Storage, collectionName, objectKey string, members []string) (int, error) {
+	var removed int
+	_, err := updateValue(s, collectionName, objectKey, object.RequestSettings{}, func(zset object.ZSet) (object.ZSet, error) {
+		zset, removed = zset.Remove(members...)
+		if removed == 0 {
+			return zset, SkipUpdate
+		}
+		return zset, nil
+	})
+	return removed, err
+}
+
+// ZSetPop removes count members w the lowest or the highest scores and returns them, sorted set w/o members is deleted
+func ZSetPop(s Storage, collectionName, objectKey string, lowest bool, count int) ([]object.ScoredMember, error) {
+	if count < 0 {
+		return nil, errors.ErrNegativeField("count")
+	}
+
+	var popped []object.ScoredMember
+	_, err := updateValue(s, collectionName, objectKey, object.RequestSettings{}, func(zset object.ZSet) (object.ZSet, error) {
+		if zset.Len() == 0 || count == 0 {
+			return zset, SkipUpdate
+		}
+
+		zset, popped = zset.Pop(lowest, count)
+		return zset, nil
+	})
+	return popped, err
+}
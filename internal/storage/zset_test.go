@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mustthink/go-storage-like-redis/internal/errors"
+	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
+)
+
+func TestZSet(t *testing.T) {
+	testStorage := New(testConfig)
+
+	added, err := ZSetAdd(testStorage, testCollection, "scores", []object.ScoredMember{{Member: "a", Score: 1}, {Member: "b", Score: 2}}, object.ZAddOptions{}, object.RequestSettings{Timeout: 100})
+	require.Nil(t, err)
+	assert.Equal(t, 2, added)
+
+	created, err := GetObject(testStorage, testCollection, "scores")
+	assert.Nil(t, created)
+	assert.Equal(t, errors.ErrWrongType, err)
+
+	// write w/o changes isn't applied
+	version := objectVersion(t, testStorage, "scores")
+	added, err = ZSetAdd(testStorage, testCollection, "scores", []object.ScoredMember{{Member: "a", Score: 5}}, object.ZAddOptions{NX: true}, object.RequestSettings{})
+	require.Nil(t, err)
+	assert.Equal(t, 0, added)
+	assert.Equal(t, version, objectVersion(t, testStorage, "scores"))
+
+	score, err := ZSetIncrement(testStorage, testCollection, "scores", "a", 2.5, object.RequestSettings{})
+	require.Nil(t, err)
+	assert.Equal(t, 3.5, score)
+
+	score, ok, err := ZSetScore(testStorage, testCollection, "scores", "a")
+	require.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 3.5, score)
+
+	rank, ok, err := ZSetRank(testStorage, testCollection, "scores", "a")
+	require.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 1, rank)
+
+	members, err := ZSetRange(testStorage, testCollection, "scores", 0, -1)
+	require.Nil(t, err)
+	assert.Equal(t, []object.ScoredMember{{Member: "b", Score: 2}, {Member: "a", Score: 3.5}}, members)
+
+	members, err = ZSetRangeByScore(testStorage, testCollection, "scores", object.ScoreBound{Score: 2, Exclusive: true}, object.ScoreBound{Score: 10}, 0)
+	require.Nil(t, err)
+	assert.Equal(t, []object.ScoredMember{{Member: "a", Score: 3.5}}, members)
+
+	// existing sorted set keeps its expiration
+	ttl, err := ObjectTTL(testStorage, testCollection, "scores")
+	require.Nil(t, err)
+	assert.Greater(t, ttl.Seconds(), float64(90))
+
+	removed, err := ZSetRemove(testStorage, testCollection, "scores", []string{"b", "unknown"})
+	require.Nil(t, err)
+	assert.Equal(t, 1, removed)
+
+	// sorted set w/o members is deleted
+	members, err = ZSetPop(testStorage, testCollection, "scores", true, 5)
+	require.Nil(t, err)
+	assert.Equal(t, []object.ScoredMember{{Member: "a", Score: 3.5}}, members)
+	assert.Equal(t, int64(0), testStorage.Stats().Memory)
+
+	_, ok, err = ZSetScore(testStorage, testCollection, "scores", "a")
+	require.Nil(t, err)
+	assert.False(t, ok)
+
+	_, err = ZSetAdd(testStorage, testCollection, "scores", []object.ScoredMember{{Member: "a", Score: 1}}, object.ZAddOptions{GT: true, LT: true}, object.RequestSettings{})
+	assert.Equal(t, errors.ErrConflictFields("gt", "lt"), err)
+
+	require.Nil(t, setObject(testStorage, testCollection, testKey, testRequestSettings))
+	_, err = ZSetAdd(testStorage, testCollection, testKey, []object.ScoredMember{{Member: "a", Score: 1}}, object.ZAddOptions{}, object.RequestSettings{})
+	assert.Equal(t, errors.ErrWrongType, err)
+	_, err = ZSetRange(testStorage, testCollection, testKey, 0, -1)
+	assert.Equal(t, errors.ErrWrongType, err)
+}
+
+func objectVersion(t *testing.T, s Storage, key string) uint64 {
+	collection, err := s.GetCollection(testCollection)
+	require.Nil(t, err)
+	obj, err := collection.Get(key)
+	require.Nil(t, err)
+	return obj.Version()
+}
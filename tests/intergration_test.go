@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -14,6 +15,7 @@ import (
 	"github.com/mustthink/go-storage-like-redis/config"
 	"github.com/mustthink/go-storage-like-redis/internal"
 	"github.com/mustthink/go-storage-like-redis/internal/handlers"
+	"github.com/mustthink/go-storage-like-redis/internal/storage"
 	"github.com/mustthink/go-storage-like-redis/internal/storage/object"
 )
 
@@ -31,47 +33,84 @@ type (
 	}
 )
 
+// testConfig is default config w admin auth
+const testConfig = "config.json"
+
 func init() {
-	path := fmt.Sprintf("../%s", config.DefaultConfig)
-	app := internal.NewApplication(path)
+	app := internal.NewApplication(testConfig)
 	// running test server
 	go app.Run()
+	waitServer("http://localhost:8081/")
 }
 
-func (c TestClient) doRequest(t *testing.T, requestMethod string, request TestRequest) handlers.DataCode {
-	var response handlers.DataCode
-	switch request.Type {
-	case handlers.TypeCollection:
-		response = &handlers.Response{}
-	case handlers.TypeObject:
-		response = &handlers.Responses{}
+func waitServer(url string) {
+	for i := 0; i < 50; i++ {
+		if _, err := http.Get(url); err == nil {
+			return
+		}
+		time.Sleep(100 * time.Millisecond)
 	}
+}
 
-	requestBody, err := json.Marshal(request)
-	if err != nil {
-		t.Errorf("couldn't marshal w err: %s", err.Error())
-		return response
+// newTestClient returns client of test server
+func newTestClient() TestClient {
+	return TestClient{
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		url: "http://localhost:8081/",
 	}
+}
 
-	req, err := http.NewRequest(requestMethod, c.url, bytes.NewBuffer(requestBody))
-	if err != nil {
-		t.Errorf("couldn't create new request w err: %s", err.Error())
-		return response
-	}
+// doRaw sends body as json to path of test server w headers and returns response w read body
+func (c TestClient) doRaw(t *testing.T, method, path string, headers http.Header, body any) (*http.Response, []byte) {
+	requestBody, err := json.Marshal(body)
+	require.Nil(t, err)
 
+	req, err := http.NewRequest(method, c.url+path, bytes.NewBuffer(requestBody))
+	require.Nil(t, err)
 	req.Header.Set("Content-Type", "application/json")
-	resp, err := c.client.Do(req)
-	if err != nil {
-		t.Errorf("couldn't do request w err: %s", err.Error())
-		return response
+	for key, values := range headers {
+		req.Header[key] = values
 	}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		t.Errorf("couldn't read response.Body w err: %s", err.Error())
-		return response
+	resp, err := c.client.Do(req)
+	require.Nil(t, err)
+	defer resp.Body.Close()
+
+	responseBody, err := io.ReadAll(resp.Body)
+	require.Nil(t, err)
+	return resp, responseBody
+}
+
+// doResponse sends request w single response like collection or command request
+func (c TestClient) doResponse(t *testing.T, method, path string, headers http.Header, body any) (*http.Response, handlers.Response) {
+	resp, responseBody := c.doRaw(t, method, path, headers, body)
+
+	var response handlers.Response
+	require.Nil(t, json.Unmarshal(responseBody, &response))
+	return resp, response
+}
+
+// doResponses sends objects request w array of responses
+func (c TestClient) doResponses(t *testing.T, method string, headers http.Header, request TestRequest) (*http.Response, handlers.Responses) {
+	resp, responseBody := c.doRaw(t, method, "", headers, request)
+
+	var responses handlers.Responses
+	require.Nil(t, json.Unmarshal(responseBody, &responses))
+	return resp, responses
+}
+
+func (c TestClient) doRequest(t *testing.T, requestMethod string, request TestRequest) handlers.DataCode {
+	var response handlers.DataCode
+	switch request.Type {
+	case handlers.TypeCollection:
+		response = &handlers.Response{}
+	case handlers.TypeObject:
+		response = &handlers.Responses{}
 	}
 
+	_, body := c.doRaw(t, requestMethod, "", nil, request)
 	if err := json.Unmarshal(body, response); err != nil {
 		t.Errorf("couldn't unmarshal response w err: %s", err.Error())
 	}
@@ -81,15 +120,7 @@ func (c TestClient) doRequest(t *testing.T, requestMethod string, request TestRe
 }
 
 func Test_BasicRequests(t *testing.T) {
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
-	url := "http://localhost:8081/"
-
-	testClient := TestClient{
-		client: client,
-		url:    url,
-	}
+	testClient := newTestClient()
 
 	tests := []struct {
 		name          string
@@ -182,12 +213,7 @@ func Test_BasicRequests(t *testing.T) {
 }
 
 func TestTTL(t *testing.T) {
-	testClient := TestClient{
-		client: &http.Client{
-			Timeout: 10 * time.Second,
-		},
-		url: "http://localhost:8081/",
-	}
+	testClient := newTestClient()
 
 	tests := []struct {
 		name          string
@@ -252,3 +278,193 @@ func TestTTL(t *testing.T) {
 		})
 	}
 }
+
+func TestVersions(t *testing.T) {
+	testClient := newTestClient()
+	setRequest := TestRequest{
+		Type: handlers.TypeObject,
+		Objects: map[string]object.RequestSettings{
+			"versioned": {Data: []byte("1")},
+		},
+	}
+	getRequest := TestRequest{
+		Type: handlers.TypeObject,
+		Keys: []string{"versioned"},
+	}
+
+	resp, _ := testClient.doResponses(t, http.MethodPost, nil, setRequest)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, responses := testClient.doResponses(t, http.MethodGet, nil, getRequest)
+	etag := resp.Header.Get("ETag")
+	require.NotEmpty(t, etag)
+	require.Equal(t, fmt.Sprintf(`"%d"`, responses[0].Version), etag)
+
+	// the first write w etag wins
+	resp, responses = testClient.doResponses(t, http.MethodPost, http.Header{"If-Match": {etag}}, setRequest)
+	require.True(t, responses[0].Success)
+	require.NotEqual(t, etag, resp.Header.Get("ETag"))
+
+	_, responses = testClient.doResponses(t, http.MethodPost, http.Header{"If-Match": {etag}}, setRequest)
+	require.Equal(t, http.StatusConflict, responses[0].Error.Code)
+
+	_, responses = testClient.doResponses(t, http.MethodDelete, http.Header{"If-Match": {etag}}, getRequest)
+	require.Equal(t, http.StatusConflict, responses[0].Error.Code)
+
+	// any existing object
+	_, responses = testClient.doResponses(t, http.MethodPost, http.Header{"If-Match": {"*"}}, setRequest)
+	require.True(t, responses[0].Success)
+
+	_, responses = testClient.doResponses(t, http.MethodDelete, http.Header{"If-Match": {"*"}}, getRequest)
+	require.True(t, responses[0].Success)
+
+	_, responses = testClient.doResponses(t, http.MethodPost, http.Header{"If-Match": {"*"}}, setRequest)
+	require.Equal(t, http.StatusConflict, responses[0].Error.Code)
+
+	manyRequest := TestRequest{
+		Type: handlers.TypeObject,
+		Objects: map[string]object.RequestSettings{
+			"versioned": {Data: []byte("1")},
+			"other":     {Data: []byte("1")},
+		},
+	}
+	for _, tc := range []struct {
+		name    string
+		ifMatch string
+		request TestRequest
+	}{
+		{name: "weak etag", ifMatch: `W/"1"`, request: setRequest},
+		{name: "etag w/o quotes", ifMatch: "1", request: setRequest},
+		{name: "many objects", ifMatch: "*", request: manyRequest},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, response := testClient.doResponse(t, http.MethodPost, "", http.Header{"If-Match": {tc.ifMatch}}, tc.request)
+			require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			require.False(t, response.Success)
+		})
+	}
+}
+
+func TestCounter(t *testing.T) {
+	testClient := newTestClient()
+
+	_, response := testClient.doResponse(t, http.MethodPost, "", nil, map[string]any{"type": handlers.TypeCounter, "key": "counter", "command": handlers.CommandIncrBy, "increment": 10})
+	require.True(t, response.Success)
+	require.Equal(t, "10", string(response.Data))
+
+	_, response = testClient.doResponse(t, http.MethodPost, "", nil, map[string]any{"type": handlers.TypeCounter, "key": "counter", "command": handlers.CommandDecr})
+	require.Equal(t, "9", string(response.Data))
+
+	_, response = testClient.doResponse(t, http.MethodPost, "", nil, map[string]any{"type": handlers.TypeCounter, "key": "counter", "command": "unknown"})
+	require.Equal(t, http.StatusBadRequest, response.Error.Code)
+}
+
+func TestCollectionExport(t *testing.T) {
+	testClient := newTestClient()
+
+	objects := make(map[string]object.RequestSettings)
+	for i := 0; i < 25; i++ {
+		objects[fmt.Sprintf("key%d", i)] = object.RequestSettings{Data: []byte("value"), Timeout: 100}
+	}
+	resp, _ := testClient.doResponse(t, http.MethodPost, "", nil, map[string]any{"type": handlers.TypeCollection, "collection": "export"})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	resp, _ = testClient.doResponses(t, http.MethodPost, nil, TestRequest{Type: handlers.TypeObject, Collection: "export", Objects: objects})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// whole collection
+	resp, response := testClient.doResponse(t, http.MethodGet, "", nil, map[string]any{"type": handlers.TypeCollection, "collection": "export"})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.True(t, response.Success)
+	require.Len(t, response.Objects, 25)
+	require.Empty(t, response.Cursor)
+	require.Equal(t, []byte("value"), response.Objects[0].Data)
+	require.InDelta(t, 100, response.Objects[0].TTL, 1)
+	require.NotZero(t, response.Objects[0].Version)
+
+	// pages w/o data
+	var (
+		keys   = make(map[string]bool)
+		cursor string
+	)
+	for {
+		_, response = testClient.doResponse(t, http.MethodGet, "", nil, map[string]any{"type": handlers.TypeCollection, "collection": "export", "count": 10, "cursor": cursor, "keys_only": true})
+		require.LessOrEqual(t, len(response.Objects), 10)
+		for _, exported := range response.Objects {
+			require.Nil(t, exported.Data)
+			keys[exported.Key] = true
+		}
+		if response.Cursor == "" {
+			break
+		}
+		cursor = response.Cursor
+	}
+	require.Len(t, keys, 25)
+
+	resp, response = testClient.doResponse(t, http.MethodGet, "", nil, map[string]any{"type": handlers.TypeCollection, "collection": "export", "cursor": "invalid"})
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	require.False(t, response.Success)
+
+	resp, _ = testClient.doResponse(t, http.MethodDelete, "", nil, map[string]any{"type": handlers.TypeCollection, "collection": "export"})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestProcedures(t *testing.T) {
+	testClient := newTestClient()
+	admin := http.Header{"Authorization": {"Basic " + base64.StdEncoding.EncodeToString([]byte("admin:admin"))}}
+
+	// admin endpoint requires admin auth
+	resp, _ := testClient.doRaw(t, http.MethodGet, "procedures", nil, handlers.ProceduresRequest{})
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	// decrements stock if it's above zero, else fails
+	procedure := storage.Procedure{
+		Keys: 1,
+		Steps: []storage.Step{
+			{Op: storage.ProcedureGet, Var: "stock"},
+			{Op: storage.ProcedureIf, Var: "stock", Compare: storage.CompareGreater, Value: storage.Operand{Data: []byte("0")}, Goto: 3},
+			{Op: storage.ProcedureFail, Message: "out of stock"},
+			{Op: storage.ProcedureIncr, Value: storage.Operand{Data: []byte("-1")}, Var: "stock"},
+			{Op: storage.ProcedureReturn, Value: storage.Operand{Var: "stock"}},
+		},
+	}
+	_, response := testClient.doResponse(t, http.MethodPost, "procedures", admin, handlers.ProceduresRequest{Name: "decrement", Procedure: procedure})
+	require.True(t, response.Success)
+
+	_, response = testClient.doResponse(t, http.MethodGet, "procedures", admin, handlers.ProceduresRequest{Name: "decrement"})
+	require.Equal(t, procedure, response.Procedures["decrement"])
+
+	_, response = testClient.doResponse(t, http.MethodPost, "", nil, map[string]any{"type": handlers.TypeCounter, "key": "stock", "command": handlers.CommandIncr})
+	require.True(t, response.Success)
+
+	call := map[string]any{"type": handlers.TypeProcedure, "name": "decrement", "keys": []storage.ObjectRef{{Key: "stock"}}}
+	_, response = testClient.doResponse(t, http.MethodPost, "", nil, call)
+	require.True(t, response.Success)
+	require.Equal(t, "0", string(response.Data))
+
+	_, response = testClient.doResponse(t, http.MethodPost, "", nil, call)
+	require.Equal(t, http.StatusBadRequest, response.Error.Code)
+
+	_, response = testClient.doResponse(t, http.MethodDelete, "procedures", admin, handlers.ProceduresRequest{Name: "decrement"})
+	require.True(t, response.Success)
+}
+
+func TestSettings(t *testing.T) {
+	testClient := newTestClient()
+
+	resp, _ := testClient.doResponse(t, http.MethodPost, "", nil, map[string]any{"type": handlers.TypeCollection, "collection": "settings", "settings": map[string]any{"max_keys": 1}})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// zero limit turns limit off, missing fields keep settings
+	update := map[string]any{"type": handlers.TypeSettings, "collection": "settings", "settings": map[string]any{"max_keys": 0, "maxmemory": 100}}
+	resp, _ = testClient.doResponse(t, http.MethodPost, "", nil, update)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	_, response := testClient.doResponse(t, http.MethodGet, "", nil, map[string]any{"type": handlers.TypeSettings, "collection": "settings"})
+	var settings config.CollectionConfig
+	require.Nil(t, json.Unmarshal(response.Data, &settings))
+	require.Equal(t, 0, settings.MaxKeys)
+	require.Equal(t, int64(100), settings.MaxMemory)
+
+	resp, _ = testClient.doResponse(t, http.MethodDelete, "", nil, map[string]any{"type": handlers.TypeCollection, "collection": "settings"})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}